@@ -0,0 +1,104 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/uraniumdawn/cinnamon/pkg/manifest"
+	"github.com/uraniumdawn/cinnamon/pkg/policy"
+)
+
+// runApply implements `cinnamon apply -f topics.yaml --cluster prod`: it prints the
+// plan of a topic manifest against the cluster and applies it once approved.
+// It returns the exit code of the process.
+func runApply(args []string) int {
+	fs := flag.NewFlagSet("apply", flag.ExitOnError)
+	file := fs.String("f", "", "Path to the topic manifest")
+	cluster := fs.String("cluster", "", "Name of the cluster from the config")
+	autoApprove := fs.Bool("auto-approve", false, "Apply the plan without asking for confirmation")
+	planOnly := fs.Bool("plan", false, "Print the plan and exit without applying it")
+	_ = fs.Parse(args)
+
+	if *file == "" || *cluster == "" {
+		fmt.Fprintln(os.Stderr, "usage: cinnamon apply -f <manifest> --cluster <name> [--plan] [--auto-approve]")
+		return 2
+	}
+
+	m, err := manifest.Load(*file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+
+	c, cc, err := newClusterClient(*cluster)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+	defer c.Close()
+
+	live, err := manifest.Live(c, m)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to read topics of cluster '%s': %s\n", *cluster, err)
+		return 1
+	}
+
+	rules, err := policy.ParseAll(cc.Policies)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+
+	defaults, err := c.TopicDefaults(policy.ConfigKeys(rules))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to read topic defaults of cluster '%s': %s\n", *cluster, err)
+		return 1
+	}
+
+	plan := manifest.NewPlan(m, live, rules, defaults)
+	fmt.Printf("Cluster: %s\n\n%s", *cluster, plan)
+
+	if plan.Count(manifest.Invalid) > 0 {
+		fmt.Fprintln(os.Stderr, "\nError: the plan has invalid changes, fix the manifest first")
+		return 1
+	}
+	if *planOnly || !plan.HasChanges() {
+		return 0
+	}
+	if cc.ReadOnly {
+		fmt.Fprintf(os.Stderr, "\nError: cluster '%s' is read-only\n", *cluster)
+		return 1
+	}
+
+	if !*autoApprove {
+		fmt.Print("\nDo you want to perform these actions? Only 'yes' will be accepted to approve.\n\nEnter a value: ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.TrimSpace(answer) != "yes" {
+			fmt.Println("\nApply cancelled.")
+			return 1
+		}
+	}
+
+	fmt.Println()
+	err = manifest.Apply(c, plan, func(change manifest.Change, err error) {
+		if err != nil {
+			fmt.Printf("%s: %s failed: %s\n", change.Topic, change.Kind, err)
+			return
+		}
+		fmt.Printf("%s: %s complete\n", change.Topic, change.Kind)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nError: %s\n", err)
+		return 1
+	}
+
+	fmt.Println("\nApply complete.")
+	return 0
+}
@@ -0,0 +1,33 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+	"github.com/uraniumdawn/cinnamon/pkg/config"
+)
+
+// newClusterClient creates an admin client for a cluster of the config file, used by
+// the commands that run without the UI. The config of the cluster is returned along
+// with the client.
+func newClusterClient(name string) (*client.Client, *config.ClusterConfig, error) {
+	cfg, err := config.LoadAppConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	for _, c := range cfg.Cinnamon.Clusters {
+		if c.Name == name {
+			kc, err := client.NewClient(c, cfg.GetAPICallTimeout())
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to connect to cluster '%s': %w", name, err)
+			}
+			return kc, c, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("cluster '%s' is not configured", name)
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+)
+
+// runExport implements `cinnamon export --cluster prod -o snapshot.yaml`: it writes a
+// snapshot of the cluster to a YAML or JSON file. It returns the exit code of the process.
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	cluster := fs.String("cluster", "", "Name of the cluster from the config")
+	output := fs.String("o", "", "Output file, JSON when it ends with .json and YAML otherwise")
+	_ = fs.Parse(args)
+
+	if *cluster == "" {
+		fmt.Fprintln(os.Stderr, "usage: cinnamon export --cluster <name> [-o <file>]")
+		return 2
+	}
+	if *output == "" {
+		*output = client.SnapshotFileName(*cluster, time.Now())
+	}
+
+	c, _, err := newClusterClient(*cluster)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+	defer c.Close()
+
+	resultCh := make(chan *client.Snapshot, 1)
+	errorCh := make(chan error, 1)
+	c.Snapshot(resultCh, errorCh)
+
+	select {
+	case snapshot := <-resultCh:
+		if err := snapshot.Write(*output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to write snapshot: %s\n", err)
+			return 1
+		}
+		fmt.Printf(
+			"Snapshot of cluster %s written to %s (%d brokers, %d topics, %d consumer groups).\n",
+			*cluster,
+			*output,
+			len(snapshot.Brokers),
+			len(snapshot.Topics),
+			len(snapshot.ConsumerGroups),
+		)
+		return 0
+	case err := <-errorCh:
+		fmt.Fprintf(os.Stderr, "Error: failed to take snapshot of cluster '%s': %s\n", *cluster, err)
+		return 1
+	}
+}
@@ -10,6 +10,7 @@ require (
 	github.com/confluentinc/confluent-kafka-go/v2 v2.13.0
 	github.com/emirpasic/gods v1.18.1
 	github.com/gdamore/tcell/v2 v2.13.8
+	github.com/linkedin/goavro/v2 v2.15.0
 	github.com/lithammer/fuzzysearch v1.1.8
 	github.com/patrickmn/go-cache v2.1.0+incompatible
 	github.com/rivo/tview v0.42.0
@@ -23,6 +24,7 @@ require (
 	github.com/clipperhouse/uax29/v2 v2.3.0 // indirect
 	github.com/gdamore/encoding v1.0.1 // indirect
 	github.com/gofrs/flock v0.12.1 // indirect
+	github.com/golang/snappy v0.0.1 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/kr/pretty v0.3.1 // indirect
 	github.com/lucasb-eyer/go-colorful v1.3.0 // indirect
@@ -21,6 +21,15 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "apply":
+			os.Exit(runApply(os.Args[2:]))
+		case "export":
+			os.Exit(runExport(os.Args[2:]))
+		}
+	}
+
 	versionFlag := flag.Bool("version", false, "Print version information and exit")
 	flag.Parse()
 
@@ -29,6 +38,12 @@ func main() {
 		os.Exit(0)
 	}
 
-	app := ui.NewApp()
+	// The app redirects stderr to its log file.
+	stderr := os.Stderr
+	app, err := ui.NewApp()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
 	app.Run()
 }
@@ -0,0 +1,190 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package audit keeps a local log of the operations changing a cluster, one JSON
+// entry per line in audit.log next to the config file.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/config"
+)
+
+// FileName is the name of the audit log in the config directory.
+const FileName = "audit.log"
+
+// Outcomes of an audited operation.
+const (
+	OK     = "ok"
+	Failed = "failed"
+)
+
+// Params are the parameters of an audited operation.
+type Params map[string]any
+
+// Entry is a single audited operation.
+type Entry struct {
+	Time     time.Time `json:"time"`
+	User     string    `json:"user"`
+	Cluster  string    `json:"cluster"`
+	Resource string    `json:"resource"`
+	Action   string    `json:"action"`
+	Params   Params    `json:"params,omitempty"`
+	Outcome  string    `json:"outcome"`
+	Error    string    `json:"error,omitempty"`
+}
+
+var mx sync.Mutex
+
+// secretArgRegex matches a command argument whose name looks like a secret, with
+// its value given after '=', ':' or a space, e.g. `-X sasl.password=s3cret`.
+var secretArgRegex = regexp.MustCompile(
+	`(?i)([\w.-]*(?:password|passwd|secret|token|credential|api[._-]?key)[\w.-]*)` +
+		`(\s*[=:]\s*|\s+)("[^"]*"|'[^']*'|[^\s"']+)`,
+)
+
+// Redacted is the value recorded in place of a secret.
+const Redacted = "***"
+
+// RedactCommand masks the values of the secret-looking arguments of a command, so
+// that it can be recorded.
+func RedactCommand(command string) string {
+	return secretArgRegex.ReplaceAllString(command, "${1}${2}"+Redacted)
+}
+
+// Path returns the path to the audit log.
+func Path() (string, error) {
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), FileName), nil
+}
+
+// Record appends an operation to the audit log, err is its outcome. A failure to
+// write the log is logged and does not affect the operation.
+func Record(cluster, resource, action string, params Params, err error) {
+	e := Entry{
+		Time:     time.Now().UTC(),
+		User:     osUser(),
+		Cluster:  cluster,
+		Resource: resource,
+		Action:   action,
+		Params:   params,
+		Outcome:  OK,
+	}
+	if err != nil {
+		e.Outcome = Failed
+		e.Error = err.Error()
+	}
+
+	if err := write(e); err != nil {
+		log.Error().Err(err).Msg("failed to write audit log")
+	}
+}
+
+func write(e Entry) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	mx.Lock()
+	defer mx.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// Read returns the entries of the audit log, oldest first. A missing log has no
+// entries, lines that are not valid entries are skipped.
+func Read() ([]Entry, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			log.Warn().Err(err).Msg("skipping invalid audit log entry")
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Matches reports whether the entry matches every whitespace separated term of a
+// filter. A term `<field>:<value>` matches the cluster, user, resource, action or
+// outcome field, any other term matches any part of the entry. Matching ignores case.
+func (e Entry) Matches(filter string) bool {
+	fields := map[string]string{
+		"cluster":  e.Cluster,
+		"user":     e.User,
+		"resource": e.Resource,
+		"action":   e.Action,
+		"outcome":  e.Outcome,
+	}
+	line, _ := json.Marshal(e)
+	text := strings.ToLower(string(line))
+
+	for _, term := range strings.Fields(strings.ToLower(filter)) {
+		if field, value, ok := strings.Cut(term, ":"); ok {
+			if actual, known := fields[field]; known {
+				if !strings.Contains(strings.ToLower(actual), value) {
+					return false
+				}
+				continue
+			}
+		}
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func osUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
@@ -0,0 +1,33 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package audit
+
+import "testing"
+
+func TestRedactCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{
+			command: "kcat -b {{bootstrap}} -X sasl.password=s3cret -t {{topic}}",
+			want:    "kcat -b {{bootstrap}} -X sasl.password=*** -t {{topic}}",
+		},
+		{
+			command: "tool --client-secret 's3 cret' --token=abc --api-key: k",
+			want:    "tool --client-secret *** --token=*** --api-key: ***",
+		},
+		{
+			command: "kafka-console-consumer --bootstrap-server {{bootstrap}} --topic {{topic}}",
+			want:    "kafka-console-consumer --bootstrap-server {{bootstrap}} --topic {{topic}}",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := RedactCommand(tt.command); got != tt.want {
+			t.Errorf("RedactCommand(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
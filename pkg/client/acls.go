@@ -0,0 +1,141 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+// ACLsResult contains the ACL bindings matching a filter.
+type ACLsResult struct {
+	Filter   kafka.ACLBindingFilter
+	Bindings kafka.ACLBindings
+}
+
+// NewACLFilter creates a filter matching bindings of any operation and permission.
+// An empty principal matches all principals.
+func NewACLFilter(
+	principal string,
+	resourceType kafka.ResourceType,
+	patternType kafka.ResourcePatternType,
+) kafka.ACLBindingFilter {
+	return kafka.ACLBindingFilter{
+		Type:                resourceType,
+		ResourcePatternType: patternType,
+		Principal:           principal,
+		Operation:           kafka.ACLOperationAny,
+		PermissionType:      kafka.ACLPermissionTypeAny,
+	}
+}
+
+// NewTopicACLFilter creates a filter matching all bindings that apply to a topic,
+// including prefixed and wildcard bindings.
+func NewTopicACLFilter(topic string) kafka.ACLBindingFilter {
+	filter := NewACLFilter("", kafka.ResourceTopic, kafka.ResourcePatternTypeMatch)
+	filter.Name = topic
+	return filter
+}
+
+// ACLs lists the ACL bindings matching a filter.
+func (client *Client) ACLs(
+	filter kafka.ACLBindingFilter,
+	resultChan chan<- *ACLsResult,
+	errorChan chan<- error,
+) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+		defer cancel()
+
+		result, err := client.DescribeACLs(ctx, filter, kafka.SetAdminRequestTimeout(client.Timeout))
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		if result.Error.Code() != kafka.ErrNoError {
+			errorChan <- fmt.Errorf("failed to describe ACLs: %s", result.Error.String())
+			return
+		}
+
+		bindings := result.ACLBindings
+		sort.Sort(bindings)
+		resultChan <- &ACLsResult{Filter: filter, Bindings: bindings}
+	}()
+}
+
+// CreateACL creates a single ACL binding.
+func (client *Client) CreateACL(
+	binding kafka.ACLBinding,
+	resultChan chan<- bool,
+	errorChan chan<- error,
+) {
+	resultChan, errorChan = audited(client.ClusterName, "acl", "create", aclParams(binding), resultChan, errorChan)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+		defer cancel()
+
+		results, err := client.CreateACLs(
+			ctx,
+			kafka.ACLBindings{binding},
+			kafka.SetAdminRequestTimeout(client.Timeout),
+		)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+
+		for _, result := range results {
+			if result.Error.Code() != kafka.ErrNoError {
+				errorChan <- fmt.Errorf("failed to create ACL: %s", result.Error.String())
+				return
+			}
+		}
+
+		resultChan <- true
+	}()
+}
+
+// DeleteACL deletes exactly the given ACL binding.
+func (client *Client) DeleteACL(
+	binding kafka.ACLBinding,
+	resultChan chan<- bool,
+	errorChan chan<- error,
+) {
+	resultChan, errorChan = audited(client.ClusterName, "acl", "delete", aclParams(binding), resultChan, errorChan)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+		defer cancel()
+
+		results, err := client.DeleteACLs(
+			ctx,
+			kafka.ACLBindingFilters{binding},
+			kafka.SetAdminRequestTimeout(client.Timeout),
+		)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+
+		deleted := 0
+		for _, result := range results {
+			if result.Error.Code() != kafka.ErrNoError {
+				errorChan <- fmt.Errorf("failed to delete ACL: %s", result.Error.String())
+				return
+			}
+			deleted += len(result.ACLBindings)
+		}
+		if deleted == 0 {
+			errorChan <- fmt.Errorf("ACL binding not found")
+			return
+		}
+
+		resultChan <- true
+	}()
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"github.com/uraniumdawn/cinnamon/pkg/audit"
+)
+
+// partialFailure is implemented by results of operations that may fail for some
+// of the resources they change.
+type partialFailure interface {
+	failure() error
+}
+
+// audited records an operation on a cluster in the audit log once its outcome is
+// sent to one of the returned channels, which forward it to resultChan or errorChan.
+func audited[T any](
+	cluster string,
+	resource, action string,
+	params audit.Params,
+	resultChan chan<- T,
+	errorChan chan<- error,
+) (chan<- T, chan<- error) {
+	results := make(chan T)
+	errs := make(chan error)
+
+	go func() {
+		select {
+		case result := <-results:
+			var err error
+			if f, ok := any(result).(partialFailure); ok {
+				err = f.failure()
+			}
+			audit.Record(cluster, resource, action, params, err)
+			resultChan <- result
+		case err := <-errs:
+			audit.Record(cluster, resource, action, params, err)
+			errorChan <- err
+		}
+	}()
+
+	return results, errs
+}
+
+func (r *DeleteConsumerGroupsResult) failure() error {
+	groups := make([]string, 0, len(r.Errors))
+	for group := range r.Errors {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	errs := make([]error, len(groups))
+	for i, group := range groups {
+		errs[i] = fmt.Errorf("%s: %w", group, r.Errors[group])
+	}
+	return errors.Join(errs...)
+}
+
+func (r *ElectLeadersResult) failure() error {
+	var errs []error
+	for _, e := range r.Elections {
+		if e.Error != nil {
+			errs = append(errs, fmt.Errorf("%s-%d: %w", e.Topic, e.Partition, e.Error))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *ProduceResult) failure() error {
+	var errs []error
+	if r.EnqueueError != nil {
+		errs = append(errs, r.EnqueueError)
+	}
+	for _, report := range r.Reports {
+		if report.Error != nil {
+			errs = append(errs, fmt.Errorf("%s-%d: %w", r.Topic, report.Partition, report.Error))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func produceParams(params ProduceParams, count int) audit.Params {
+	p := audit.Params{"topic": params.Topic, "partition": "any", "count": count}
+	if params.Partition != kafka.PartitionAny {
+		p["partition"] = params.Partition
+	}
+	return p
+}
+
+func aclParams(binding kafka.ACLBinding) audit.Params {
+	return audit.Params{
+		"principal":     binding.Principal,
+		"host":          binding.Host,
+		"operation":     binding.Operation.String(),
+		"permission":    binding.PermissionType.String(),
+		"resource_type": binding.Type.String(),
+		"resource":      binding.Name,
+		"pattern_type":  binding.ResourcePatternType.String(),
+	}
+}
+
+func scramParams(credential ScramCredential) audit.Params {
+	return audit.Params{
+		"user":       credential.User,
+		"mechanism":  credential.Mechanism.String(),
+		"iterations": credential.Iterations,
+	}
+}
+
+func electionParams(params ElectLeadersParams) audit.Params {
+	p := audit.Params{"type": "preferred"}
+	if params.Type == kafka.ElectionTypeUnclean {
+		p["type"] = "unclean"
+	}
+	if params.Topic != "" {
+		p["topic"] = params.Topic
+	}
+	if len(params.Partitions) > 0 {
+		p["partitions"] = params.Partitions
+	}
+	if params.Broker != AnyBroker {
+		p["broker"] = params.Broker
+	}
+	return p
+}
@@ -9,6 +9,8 @@ package client
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,6 +18,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"golang.org/x/exp/maps"
 
+	"github.com/uraniumdawn/cinnamon/pkg/audit"
 	"github.com/uraniumdawn/cinnamon/pkg/config"
 )
 
@@ -23,6 +26,8 @@ import (
 type ClusterResult struct {
 	Name string
 	kafka.DescribeClusterResult
+	// Token is the OAuth token of the admin client, nil when it has none.
+	Token *Token
 }
 
 // ResourceResult contains configuration resource results.
@@ -43,6 +48,7 @@ type TopicResult struct {
 	Config       []kafka.ConfigResourceResult
 	startOffsets map[int32]kafka.Offset
 	endOffsets   map[int32]kafka.Offset
+	throughput   *Throughput
 	mx           sync.RWMutex
 }
 
@@ -60,6 +66,22 @@ type DescribeConsumerGroupResult struct {
 	mx             sync.RWMutex
 }
 
+// DeleteConsumerGroupsResult contains the outcome of deleting consumer groups.
+type DeleteConsumerGroupsResult struct {
+	Deleted []string
+	// Errors holds the reason per group that was not deleted.
+	Errors map[string]error
+}
+
+// DeleteRecordsResult contains the watermarks of a topic after records were deleted.
+type DeleteRecordsResult struct {
+	Topic string
+	// Partitions lists the partitions records were deleted from.
+	Partitions []int32
+	Low        map[int32]kafka.Offset
+	High       map[int32]kafka.Offset
+}
+
 // TopicPartition represents a topic and partition pair.
 type TopicPartition struct {
 	Topic     string
@@ -71,11 +93,29 @@ type Client struct {
 	ClusterName string
 	Timeout     time.Duration
 	*kafka.AdminClient
+
+	mx         sync.RWMutex
+	connection Connection
+	stop       chan struct{}
+	closeOnce  sync.Once
+
+	// tokens obtains the OAuth tokens of the cluster. The admin client is then
+	// derived from producer, whose events channel delivers the refresh events.
+	tokens   *tokenProvider
+	producer *kafka.Producer
 }
 
-func NewClient(config *config.ClusterConfig, timeout time.Duration) (*Client, error) {
+// newConfigMap builds a librdkafka configuration from the cluster properties,
+// resolving their secret references, and routes librdkafka logs to the
+// application logger.
+func newConfigMap(config *config.ClusterConfig) (*kafka.ConfigMap, error) {
+	properties, err := config.ResolvedProperties()
+	if err != nil {
+		return nil, err
+	}
+
 	conf := &kafka.ConfigMap{}
-	for key, value := range config.Properties {
+	for key, value := range properties {
 		_ = conf.SetKey(key, value)
 	}
 
@@ -90,17 +130,63 @@ func NewClient(config *config.ClusterConfig, timeout time.Duration) (*Client, er
 		}
 	}()
 
-	adminClient, err := kafka.NewAdminClient(conf)
+	return conf, nil
+}
+
+func NewClient(config *config.ClusterConfig, timeout time.Duration) (*Client, error) {
+	conf, err := newConfigMap(config)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to resolve cluster properties")
+		return nil, err
+	}
+	tokens, err := newTokenProvider(config, timeout)
 	if err != nil {
-		log.Error().Err(err).Msg("failed to create Admin client")
 		return nil, err
 	}
 
-	return &Client{
+	client := &Client{
 		ClusterName: config.Name,
 		Timeout:     timeout,
-		AdminClient: adminClient,
-	}, nil
+		connection:  Connection{State: Connecting},
+		stop:        make(chan struct{}),
+		tokens:      tokens,
+	}
+
+	if tokens == nil {
+		client.AdminClient, err = kafka.NewAdminClient(conf)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to create Admin client")
+			return nil, err
+		}
+		return client, nil
+	}
+
+	// A standalone admin client has no events channel to receive the
+	// OAuthBearerTokenRefresh events librdkafka sends on start and before
+	// every token expires.
+	client.producer, err = kafka.NewProducer(conf)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to create Admin client")
+		return nil, err
+	}
+	go func() {
+		for event := range client.producer.Events() {
+			switch e := event.(type) {
+			case kafka.OAuthBearerTokenRefresh:
+				tokens.refresh(client.producer)
+			case kafka.Error:
+				log.Debug().Err(e).Str("cluster", config.Name).Msg("admin client error")
+			}
+		}
+	}()
+
+	client.AdminClient, err = kafka.NewAdminClientFromProducer(client.producer)
+	if err != nil {
+		client.producer.Close()
+		log.Error().Err(err).Msg("failed to create Admin client")
+		return nil, err
+	}
+	return client, nil
 }
 
 // DescribeCluster retrieves cluster description including nodes and authorized operations.
@@ -118,7 +204,10 @@ func (client *Client) DescribeCluster(resultChan chan<- *ClusterResult, errorCha
 			return
 		}
 
-		result := &ClusterResult{client.ClusterName, clusterDesc}
+		result := &ClusterResult{Name: client.ClusterName, DescribeClusterResult: clusterDesc}
+		if token, ok := client.Token(); ok {
+			result.Token = &token
+		}
 		resultChan <- result
 	}()
 }
@@ -198,6 +287,65 @@ func (client *Client) ConsumerGroups(
 	}()
 }
 
+// DeleteConsumerGroups deletes consumer groups. Groups that still have active
+// members are refused before the request is sent to the cluster.
+func (client *Client) DeleteConsumerGroups(
+	groups []string,
+	resultChan chan<- *DeleteConsumerGroupsResult,
+	errorChan chan<- error,
+) {
+	resultChan, errorChan = audited(client.ClusterName, "consumer_group", "delete",
+		audit.Params{"groups": groups}, resultChan, errorChan)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+		defer cancel()
+
+		result := &DeleteConsumerGroupsResult{Errors: make(map[string]error)}
+
+		descriptions, err := client.DescribeConsumerGroups(ctx, groups)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		var deletable []string
+		for _, desc := range descriptions.ConsumerGroupDescriptions {
+			switch {
+			case desc.Error.Code() != kafka.ErrNoError:
+				result.Errors[desc.GroupID] = desc.Error
+			case len(desc.Members) > 0:
+				result.Errors[desc.GroupID] = fmt.Errorf(
+					"group has %d active members",
+					len(desc.Members),
+				)
+			default:
+				deletable = append(deletable, desc.GroupID)
+			}
+		}
+
+		if len(deletable) > 0 {
+			results, err := client.AdminClient.DeleteConsumerGroups(
+				ctx,
+				deletable,
+				kafka.SetAdminRequestTimeout(client.Timeout),
+			)
+			if err != nil {
+				errorChan <- err
+				return
+			}
+			for _, r := range results.ConsumerGroupResults {
+				if r.Error.Code() != kafka.ErrNoError {
+					result.Errors[r.Group] = r.Error
+					continue
+				}
+				result.Deleted = append(result.Deleted, r.Group)
+			}
+		}
+
+		resultChan <- result
+	}()
+}
+
 func (client *Client) CreateTopic(
 	name string,
 	numPartitions int,
@@ -206,6 +354,13 @@ func (client *Client) CreateTopic(
 	resultChan chan<- bool,
 	errorChan chan<- error,
 ) {
+	resultChan, errorChan = audited(client.ClusterName, "topic", "create", audit.Params{
+		"topic":              name,
+		"partitions":         numPartitions,
+		"replication_factor": replicationFactor,
+		"config":             config,
+	}, resultChan, errorChan)
+
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
 		defer cancel()
@@ -243,6 +398,9 @@ func (client *Client) DeleteTopic(
 	resultChan chan<- bool,
 	errorChan chan<- error,
 ) {
+	resultChan, errorChan = audited(client.ClusterName, "topic", "delete",
+		audit.Params{"topic": name}, resultChan, errorChan)
+
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
 		defer cancel()
@@ -268,12 +426,134 @@ func (client *Client) DeleteTopic(
 	}()
 }
 
+// DeleteRecords deletes records of a topic before the given offsets per partition.
+// kafka.OffsetEnd truncates a partition up to its end offset and an empty map
+// purges all partitions of the topic.
+func (client *Client) DeleteRecords(
+	topic string,
+	offsets map[int32]kafka.Offset,
+	resultChan chan<- *DeleteRecordsResult,
+	errorChan chan<- error,
+) {
+	resultChan, errorChan = audited(client.ClusterName, "topic", "delete_records",
+		audit.Params{"topic": topic, "offsets": offsets}, resultChan, errorChan)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+		defer cancel()
+
+		desc, err := client.DescribeTopics(ctx, kafka.NewTopicCollectionOfTopicNames([]string{topic}))
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		var all []int32
+		for _, d := range desc.TopicDescriptions {
+			if d.Error.Code() != kafka.ErrNoError {
+				errorChan <- fmt.Errorf("failed to describe topic '%s': %s", topic, d.Error.String())
+				return
+			}
+			for _, p := range d.Partitions {
+				all = append(all, int32(p.Partition))
+			}
+		}
+
+		if len(offsets) == 0 {
+			offsets = make(map[int32]kafka.Offset)
+			for _, p := range all {
+				offsets[p] = kafka.OffsetEnd
+			}
+		}
+
+		result := &DeleteRecordsResult{Topic: topic}
+		var rq []kafka.TopicPartition
+		for p, offset := range offsets {
+			if !slices.Contains(all, p) {
+				errorChan <- fmt.Errorf("topic '%s' has no partition %d", topic, p)
+				return
+			}
+			rq = append(rq, kafka.TopicPartition{Topic: &topic, Partition: p, Offset: offset})
+			result.Partitions = append(result.Partitions, p)
+		}
+		slices.Sort(result.Partitions)
+
+		deleted, err := client.AdminClient.DeleteRecords(
+			ctx,
+			rq,
+			kafka.SetAdminRequestTimeout(client.Timeout),
+		)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		for _, r := range deleted.DeleteRecordsResults {
+			if r.TopicPartition.Error != nil {
+				errorChan <- fmt.Errorf(
+					"failed to delete records of partition %d: %w",
+					r.TopicPartition.Partition,
+					r.TopicPartition.Error,
+				)
+				return
+			}
+		}
+
+		result.Low, result.High, err = client.TopicOffsets(ctx, topic, all)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		resultChan <- result
+	}()
+}
+
+// CreatePartitions increases the partition count of a topic.
+func (client *Client) CreatePartitions(
+	name string,
+	count int,
+	resultChan chan<- bool,
+	errorChan chan<- error,
+) {
+	resultChan, errorChan = audited(client.ClusterName, "topic", "create_partitions",
+		audit.Params{"topic": name, "partitions": count}, resultChan, errorChan)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+		defer cancel()
+
+		results, err := client.AdminClient.CreatePartitions(
+			ctx,
+			[]kafka.PartitionsSpecification{{Topic: name, IncreaseTo: count}},
+			kafka.SetAdminRequestTimeout(client.Timeout),
+		)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+
+		for _, result := range results {
+			if result.Error.Code() != kafka.ErrNoError {
+				errorChan <- fmt.Errorf(
+					"failed to create partitions of topic '%s': %s",
+					name,
+					result.Error.String(),
+				)
+				return
+			}
+		}
+
+		resultChan <- true
+	}()
+}
+
 func (client *Client) UpdateTopicConfig(
 	name string,
 	config map[string]string,
 	resultChan chan<- bool,
 	errorChan chan<- error,
 ) {
+	resultChan, errorChan = audited(client.ClusterName, "topic", "update_config",
+		audit.Params{"topic": name, "config": config}, resultChan, errorChan)
+
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
 		defer cancel()
@@ -320,6 +600,63 @@ func (client *Client) UpdateTopicConfig(
 	}()
 }
 
+// ClusterDefaultBroker is the broker name addressing the cluster-wide default
+// of dynamic broker configs.
+const ClusterDefaultBroker = ""
+
+// UpdateBrokerConfig sets or deletes a dynamic config entry of a broker or, for
+// ClusterDefaultBroker, the cluster-wide default. Deleting an override reverts
+// the entry to the next source in precedence.
+func (client *Client) UpdateBrokerConfig(
+	brokerID string,
+	entry string,
+	value string,
+	op kafka.AlterConfigOpType,
+	resultChan chan<- bool,
+	errorChan chan<- error,
+) {
+	params := audit.Params{"broker": brokerID, "entry": entry}
+	if op != kafka.AlterConfigOpTypeDelete {
+		params["value"] = value
+	}
+	resultChan, errorChan = audited(client.ClusterName, "broker_config", strings.ToLower(op.String()),
+		params, resultChan, errorChan)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+		defer cancel()
+
+		configResource := kafka.ConfigResource{
+			Type: kafka.ResourceBroker,
+			Name: brokerID,
+			Config: []kafka.ConfigEntry{{
+				Name:                 entry,
+				Value:                value,
+				IncrementalOperation: op,
+			}},
+		}
+
+		results, err := client.IncrementalAlterConfigs(
+			ctx,
+			[]kafka.ConfigResource{configResource},
+			kafka.SetAdminRequestTimeout(client.Timeout),
+		)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+
+		for _, result := range results {
+			if result.Error.Code() != kafka.ErrNoError {
+				errorChan <- fmt.Errorf("failed to update broker config '%s': %s", entry, result.Error.String())
+				return
+			}
+		}
+
+		resultChan <- true
+	}()
+}
+
 func (client *Client) DescribeConsumerGroup(
 	group string,
 	resultChan chan<- *DescribeConsumerGroupResult,
@@ -434,6 +771,13 @@ func (r *DescribeConsumerGroupResult) SetEndOffsets(o map[TopicPartition]kafka.O
 	r.logEndOffsets = o
 }
 
+// Offsets returns the committed and log end offsets per partition.
+func (r *DescribeConsumerGroupResult) Offsets() (current, end map[TopicPartition]kafka.Offset) {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+	return r.currentOffsets, r.logEndOffsets
+}
+
 func (r *TopicResult) SetStartOffsets(o map[int32]kafka.Offset) {
 	r.mx.Lock()
 	defer r.mx.Unlock()
@@ -458,6 +802,24 @@ func (r *TopicResult) SetTopicACLsResult(o kafka.DescribeACLsResult) {
 	r.DescribeACLsResult = o
 }
 
+// SetThroughput sets the sampled produce rates shown in the topic description.
+func (r *TopicResult) SetThroughput(t *Throughput) {
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	r.throughput = t
+}
+
+// Partitions returns the partition ids of the described topic.
+func (r *TopicResult) Partitions() []int32 {
+	var partitions []int32
+	for _, desc := range r.TopicDescriptions {
+		for _, p := range desc.Partitions {
+			partitions = append(partitions, int32(p.Partition))
+		}
+	}
+	return partitions
+}
+
 func (client *Client) DescribeTopic(
 	name string,
 	resultChan chan<- *TopicResult,
@@ -493,61 +855,38 @@ func (client *Client) DescribeTopic(
 			}
 		}()
 
-		//go func() {
-		//	defer wg.Done()
-		//	binding := kafka.ACLBindingFilter{
-		//		Type: kafka.ResourceTopic,
-		//		Name: name,
-		//	}
-		//
-		//	ac, errorACLs := client.DescribeACLs(context.Background(), binding)
-		//	//topicResult.SetTopicACLsResult(*ac)
-		//	fmt.Printf("DescribeACLs successful, result: %s", ac)
-		//	if errorACLs != nil {
-		//		errorChan <- errorACLs
-		//		return
-		//	}
-		//}()
-
-		startOffsetsRq := make(map[kafka.TopicPartition]kafka.OffsetSpec)
-		endOffsetsRq := make(map[kafka.TopicPartition]kafka.OffsetSpec)
-		for _, d := range desc.TopicDescriptions {
-			for _, p := range d.Partitions {
-				tp := kafka.TopicPartition{Topic: &name, Partition: int32(p.Partition)}
-				startOffsetsRq[tp] = kafka.EarliestOffsetSpec
-				endOffsetsRq[tp] = kafka.LatestOffsetSpec
-			}
-		}
-
-		toOffsetsByPartition := func(result kafka.ListOffsetsResult) map[int32]kafka.Offset {
-			r := make(map[int32]kafka.Offset)
-			for tp, info := range result.ResultInfos {
-				r[tp.Partition] = info.Offset
-			}
-			return r
-		}
-
+		// ACLs are optional for the description, clusters without an
+		// authorizer reject the request and the error is shown instead
 		go func() {
 			defer wg.Done()
-			st, err := client.ListOffsets(ctx, startOffsetsRq,
-				kafka.SetAdminIsolationLevel(kafka.IsolationLevelReadCommitted))
-			if err != nil {
-				errorChan <- err
+			acls, errACLs := client.DescribeACLs(ctx, NewTopicACLFilter(name))
+			if errACLs != nil {
+				kafkaErr, ok := errACLs.(kafka.Error)
+				if !ok {
+					kafkaErr = kafka.NewError(kafka.ErrUnknown, errACLs.Error(), false)
+				}
+				topicResult.SetTopicACLsResult(kafka.DescribeACLsResult{Error: kafkaErr})
 				return
 			}
-
-			topicResult.SetStartOffsets(toOffsetsByPartition(st))
+			topicResult.SetTopicACLsResult(*acls)
 		}()
 
+		var partitions []int32
+		for _, d := range desc.TopicDescriptions {
+			for _, p := range d.Partitions {
+				partitions = append(partitions, int32(p.Partition))
+			}
+		}
+
 		go func() {
 			defer wg.Done()
-			end, err := client.ListOffsets(ctx, endOffsetsRq,
-				kafka.SetAdminIsolationLevel(kafka.IsolationLevelReadCommitted))
+			start, end, err := client.TopicOffsets(ctx, name, partitions)
 			if err != nil {
 				errorChan <- err
 				return
 			}
-			topicResult.SetEndOffsets(toOffsetsByPartition(end))
+			topicResult.SetStartOffsets(start)
+			topicResult.SetEndOffsets(end)
 		}()
 
 		wg.Wait()
@@ -555,6 +894,65 @@ func (client *Client) DescribeTopic(
 	}()
 }
 
+// TopicOffsets lists the start and end offsets of the given partitions of a topic.
+func (client *Client) TopicOffsets(
+	ctx context.Context,
+	name string,
+	partitions []int32,
+) (map[int32]kafka.Offset, map[int32]kafka.Offset, error) {
+	return topicOffsets(ctx, client.AdminClient, name, partitions)
+}
+
+// topicOffsets lists the start and end offsets of partitions of a topic in one
+// request each.
+func topicOffsets(
+	ctx context.Context,
+	admin *kafka.AdminClient,
+	name string,
+	partitions []int32,
+) (map[int32]kafka.Offset, map[int32]kafka.Offset, error) {
+	startOffsetsRq := make(map[kafka.TopicPartition]kafka.OffsetSpec)
+	endOffsetsRq := make(map[kafka.TopicPartition]kafka.OffsetSpec)
+	for _, p := range partitions {
+		tp := kafka.TopicPartition{Topic: &name, Partition: p}
+		startOffsetsRq[tp] = kafka.EarliestOffsetSpec
+		endOffsetsRq[tp] = kafka.LatestOffsetSpec
+	}
+
+	toOffsetsByPartition := func(result kafka.ListOffsetsResult) map[int32]kafka.Offset {
+		r := make(map[int32]kafka.Offset)
+		for tp, info := range result.ResultInfos {
+			r[tp.Partition] = info.Offset
+		}
+		return r
+	}
+
+	var st, end kafka.ListOffsetsResult
+	var stErr, endErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		st, stErr = admin.ListOffsets(ctx, startOffsetsRq,
+			kafka.SetAdminIsolationLevel(kafka.IsolationLevelReadCommitted))
+	}()
+
+	go func() {
+		defer wg.Done()
+		end, endErr = admin.ListOffsets(ctx, endOffsetsRq,
+			kafka.SetAdminIsolationLevel(kafka.IsolationLevelReadCommitted))
+	}()
+
+	wg.Wait()
+	if stErr != nil {
+		return nil, nil, stErr
+	}
+	if endErr != nil {
+		return nil, nil, endErr
+	}
+	return toOffsetsByPartition(st), toOffsetsByPartition(end), nil
+}
+
 func (client *Client) DescribeTopicConfig(name string) (*[]kafka.ConfigResourceResult, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
 	defer cancel()
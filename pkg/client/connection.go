@@ -0,0 +1,152 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ConnectionState tells whether a cluster answers the probes of its client.
+type ConnectionState string
+
+const (
+	// Connecting is the state until the first probe completes.
+	Connecting ConnectionState = "connecting"
+	// Healthy marks a cluster answering with a controller and all of its brokers.
+	Healthy ConnectionState = "healthy"
+	// Degraded marks a cluster answering without a controller or with fewer brokers
+	// than before, or a reachable cluster whose last probes failed.
+	Degraded ConnectionState = "degraded"
+	// Unreachable marks a cluster that does not answer the probes.
+	Unreachable ConnectionState = "unreachable"
+)
+
+const (
+	// ProbeInterval is the time between probes of a reachable cluster.
+	ProbeInterval = 30 * time.Second
+	// RetryMinInterval is the delay before probing again after a failed probe,
+	// doubled after every further failed probe up to RetryMaxInterval.
+	RetryMinInterval = 2 * time.Second
+	// RetryMaxInterval bounds the delay between failed probes.
+	RetryMaxInterval = time.Minute
+	// DegradedProbes is the number of failed probes after which a reachable
+	// cluster is considered unreachable.
+	DegradedProbes = 3
+)
+
+// Connection is the outcome of the probes of a cluster.
+type Connection struct {
+	State ConnectionState
+	// Err is why the cluster is not healthy.
+	Err error
+	// Brokers is the number of brokers that answered the last successful probe.
+	Brokers int
+	// Failures counts the consecutive failed probes.
+	Failures int
+	// Checked is the time of the last probe.
+	Checked time.Time
+	// maxBrokers is the largest number of brokers seen since the client was created.
+	maxBrokers int
+}
+
+// Connection returns the current state of the connection to the cluster.
+func (client *Client) Connection() Connection {
+	client.mx.RLock()
+	defer client.mx.RUnlock()
+	return client.connection
+}
+
+// Watch probes the cluster in the background until the client is closed. Failed
+// probes are retried with an exponential backoff. onChange is
+// called from the probing goroutine whenever the state changes.
+func (client *Client) Watch(onChange func(previous ConnectionState, current Connection)) {
+	go func() {
+		for {
+			brokers, controller, err := client.probe()
+
+			client.mx.Lock()
+			previous := client.connection
+			client.connection = previous.next(brokers, controller, err)
+			current := client.connection
+			client.mx.Unlock()
+
+			if current.State != previous.State {
+				onChange(previous.State, current)
+			}
+
+			select {
+			case <-client.stop:
+				return
+			case <-time.After(current.retryIn()):
+			}
+		}
+	}()
+}
+
+// Close stops watching the cluster and closes the admin client.
+func (client *Client) Close() {
+	client.closeOnce.Do(func() { close(client.stop) })
+	client.AdminClient.Close()
+	if client.producer != nil {
+		client.producer.Close()
+	}
+}
+
+func (client *Client) probe() (int, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+	defer cancel()
+
+	desc, err := client.AdminClient.DescribeCluster(ctx)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("no answer within %s", client.Timeout)
+		}
+		return 0, false, err
+	}
+	return len(desc.Nodes), desc.Controller != nil, nil
+}
+
+func (c Connection) next(brokers int, controller bool, err error) Connection {
+	c.Checked = time.Now()
+
+	if err != nil {
+		reachable := c.State == Healthy || c.State == Degraded
+		c.Failures++
+		c.Err = err
+		c.State = Unreachable
+		if reachable && c.Failures < DegradedProbes {
+			c.State = Degraded
+		}
+		return c
+	}
+
+	c.Failures = 0
+	c.Brokers = brokers
+	c.maxBrokers = max(c.maxBrokers, brokers)
+	switch {
+	case !controller:
+		c.State, c.Err = Degraded, errors.New("no active controller")
+	case brokers < c.maxBrokers:
+		c.State = Degraded
+		c.Err = fmt.Errorf("%d of %d brokers available", brokers, c.maxBrokers)
+	default:
+		c.State, c.Err = Healthy, nil
+	}
+	return c
+}
+
+func (c Connection) retryIn() time.Duration {
+	if c.Failures == 0 {
+		return ProbeInterval
+	}
+	delay := RetryMinInterval
+	for i := 1; i < c.Failures && delay < RetryMaxInterval; i++ {
+		delay *= 2
+	}
+	return min(delay, RetryMaxInterval)
+}
@@ -0,0 +1,381 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/config"
+)
+
+// Position defines where reading of a partition starts.
+type Position string
+
+const (
+	// PositionBeginning starts reading from the low watermark.
+	PositionBeginning Position = "beginning"
+	// PositionEnd starts reading so that the latest records of the partition are returned.
+	PositionEnd Position = "end"
+	// PositionOffset starts reading from an explicit offset.
+	PositionOffset Position = "offset"
+	// PositionTimestamp starts reading from the first record at or after a timestamp.
+	PositionTimestamp Position = "timestamp"
+)
+
+// Positions lists all supported start positions.
+var Positions = []Position{PositionBeginning, PositionEnd, PositionOffset, PositionTimestamp}
+
+// ConsumeParams describes a bounded read of a single partition.
+type ConsumeParams struct {
+	Topic     string
+	Partition int32
+	Position  Position
+	// Offset is used with PositionOffset.
+	Offset int64
+	// Timestamp in milliseconds since epoch, used with PositionTimestamp.
+	Timestamp int64
+	Limit     int
+}
+
+// Record is a single consumed message.
+type Record struct {
+	Topic     string
+	Partition int32
+	Offset    kafka.Offset
+	Key       []byte
+	Value     []byte
+	Headers   []kafka.Header
+	Timestamp time.Time
+	// DecodedKey and DecodedValue hold the schema based rendering of
+	// the key and value, empty when the record is not schema encoded.
+	DecodedKey   string
+	DecodedValue string
+}
+
+// RecordsResult contains a page of records together with the partition watermarks.
+type RecordsResult struct {
+	Params  ConsumeParams
+	Records []Record
+	Low     int64
+	High    int64
+	// Start is the offset the page was read from.
+	Start int64
+	// Next is the offset the following page starts from.
+	Next int64
+}
+
+// PeekResult contains the latest records of every partition of a topic.
+type PeekResult struct {
+	Topic   string
+	Limit   int
+	Records []Record
+}
+
+// Consumer wraps the Kafka Consumer with cluster name context.
+// It never joins a consumer group and never commits offsets, partitions
+// are assigned explicitly for every read.
+type Consumer struct {
+	ClusterName string
+	Timeout     time.Duration
+	*kafka.Consumer
+	// admin is derived from the consumer to list offsets of many partitions at once.
+	admin *kafka.AdminClient
+	mx    sync.Mutex
+	// refreshToken requests a refresh of the OAuth token, which is kept fresh
+	// in the background since the consumer receives refresh events only while
+	// reading.
+	refreshToken func()
+}
+
+func NewConsumer(config *config.ClusterConfig, timeout time.Duration) (*Consumer, error) {
+	conf, err := newConfigMap(config)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to resolve cluster properties")
+		return nil, err
+	}
+	tokens, err := newTokenProvider(config, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := config.Properties["group.id"]; !ok {
+		_ = conf.SetKey("group.id", "cinnamon-"+config.Name)
+	}
+	_ = conf.SetKey("enable.auto.commit", false)
+	_ = conf.SetKey("enable.auto.offset.store", false)
+	_ = conf.SetKey("auto.offset.reset", "earliest")
+
+	consumer, err := kafka.NewConsumer(conf)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to create Consumer")
+		return nil, err
+	}
+	admin, err := kafka.NewAdminClientFromConsumer(consumer)
+	if err != nil {
+		consumer.Close()
+		log.Error().Err(err).Msg("failed to create Consumer")
+		return nil, err
+	}
+
+	refreshToken := func() {}
+	if tokens != nil {
+		refreshToken = tokens.keepRefreshed(consumer)
+	}
+
+	return &Consumer{
+		ClusterName:  config.Name,
+		Timeout:      timeout,
+		Consumer:     consumer,
+		admin:        admin,
+		refreshToken: refreshToken,
+	}, nil
+}
+
+// deadline returns the time a read started now must complete by. Callers wait
+// for the result with the same timeout, part of it is left to them.
+func (consumer *Consumer) deadline() time.Time {
+	return time.Now().Add(consumer.Timeout * 3 / 4)
+}
+
+// remainingMs returns the time left until the deadline in milliseconds, at
+// least one so that a late call fails on its own timeout instead of blocking.
+func remainingMs(deadline time.Time) int {
+	return max(int(time.Until(deadline).Milliseconds()), 1)
+}
+
+// Consume reads up to params.Limit records of a single partition starting from
+// the requested position. Reading stops at the high watermark, so the call
+// never waits for new records to arrive.
+func (consumer *Consumer) Consume(
+	params ConsumeParams,
+	resultChan chan<- *RecordsResult,
+	errorChan chan<- error,
+) {
+	go func() {
+		consumer.mx.Lock()
+		result, err := consumer.read(params)
+		consumer.mx.Unlock()
+
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		resultChan <- result
+	}()
+}
+
+func (consumer *Consumer) read(params ConsumeParams) (*RecordsResult, error) {
+	deadline := consumer.deadline()
+	low, high, err := consumer.QueryWatermarkOffsets(
+		params.Topic,
+		params.Partition,
+		remainingMs(deadline),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watermarks: %w", err)
+	}
+
+	start, err := consumer.startOffset(params, low, high, deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RecordsResult{Params: params, Low: low, High: high, Start: start, Next: start}
+	if start >= high || params.Limit <= 0 {
+		return result, nil
+	}
+
+	err = consumer.Assign([]kafka.TopicPartition{{
+		Topic:     &params.Topic,
+		Partition: params.Partition,
+		Offset:    kafka.Offset(start),
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign partition: %w", err)
+	}
+	defer func() {
+		if err := consumer.Unassign(); err != nil {
+			log.Error().Err(err).Msg("failed to unassign partition")
+		}
+	}()
+
+	// Records of compacted or transactional topics may not fill the range up
+	// to the high watermark, so polling stops at the deadline too.
+	for len(result.Records) < params.Limit && result.Next < high && time.Now().Before(deadline) {
+		switch e := consumer.Poll(100).(type) {
+		case *kafka.Message:
+			if e.TopicPartition.Error != nil {
+				return nil, e.TopicPartition.Error
+			}
+			result.Records = append(result.Records, toRecord(e))
+			result.Next = int64(e.TopicPartition.Offset) + 1
+		case kafka.OAuthBearerTokenRefresh:
+			consumer.refreshToken()
+		case kafka.Error:
+			if e.IsFatal() {
+				return nil, e
+			}
+			log.Warn().Err(e).Msg("consumer error")
+		}
+	}
+
+	return result, nil
+}
+
+// Peek reads up to limit latest records of every partition of a topic.
+func (consumer *Consumer) Peek(
+	topic string,
+	limit int,
+	resultChan chan<- *PeekResult,
+	errorChan chan<- error,
+) {
+	go func() {
+		consumer.mx.Lock()
+		result, err := consumer.peek(topic, limit)
+		consumer.mx.Unlock()
+
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		resultChan <- result
+	}()
+}
+
+func (consumer *Consumer) peek(topic string, limit int) (*PeekResult, error) {
+	deadline := consumer.deadline()
+	metadata, err := consumer.GetMetadata(&topic, false, remainingMs(deadline))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get topic metadata: %w", err)
+	}
+	tm, ok := metadata.Topics[topic]
+	if !ok {
+		return nil, fmt.Errorf("topic '%s' not found", topic)
+	}
+	if tm.Error.Code() != kafka.ErrNoError {
+		return nil, tm.Error
+	}
+
+	partitions := make([]int32, len(tm.Partitions))
+	for i, p := range tm.Partitions {
+		partitions[i] = p.ID
+	}
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	lows, highs, err := topicOffsets(ctx, consumer.admin, topic, partitions)
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list offsets: %w", err)
+	}
+
+	result := &PeekResult{Topic: topic, Limit: limit}
+	remaining := make(map[int32]int64)
+	var assignment []kafka.TopicPartition
+	for _, p := range tm.Partitions {
+		low, high := int64(lows[p.ID]), int64(highs[p.ID])
+		start := max(low, high-int64(limit))
+		if start >= high {
+			continue
+		}
+		remaining[p.ID] = high
+		assignment = append(assignment, kafka.TopicPartition{
+			Topic:     &topic,
+			Partition: p.ID,
+			Offset:    kafka.Offset(start),
+		})
+	}
+
+	if len(assignment) == 0 {
+		return result, nil
+	}
+
+	if err := consumer.Assign(assignment); err != nil {
+		return nil, fmt.Errorf("failed to assign partitions: %w", err)
+	}
+	defer func() {
+		if err := consumer.Unassign(); err != nil {
+			log.Error().Err(err).Msg("failed to unassign partitions")
+		}
+	}()
+
+	for len(remaining) > 0 && time.Now().Before(deadline) {
+		switch e := consumer.Poll(100).(type) {
+		case *kafka.Message:
+			if e.TopicPartition.Error != nil {
+				return nil, e.TopicPartition.Error
+			}
+			result.Records = append(result.Records, toRecord(e))
+			p := e.TopicPartition.Partition
+			if int64(e.TopicPartition.Offset)+1 >= remaining[p] {
+				delete(remaining, p)
+			}
+		case kafka.OAuthBearerTokenRefresh:
+			consumer.refreshToken()
+		case kafka.Error:
+			if e.IsFatal() {
+				return nil, e
+			}
+			log.Warn().Err(e).Msg("consumer error")
+		}
+	}
+
+	sort.Slice(result.Records, func(i, j int) bool {
+		a, b := result.Records[i], result.Records[j]
+		if a.Partition == b.Partition {
+			return a.Offset < b.Offset
+		}
+		return a.Partition < b.Partition
+	})
+
+	return result, nil
+}
+
+func (consumer *Consumer) startOffset(
+	params ConsumeParams,
+	low, high int64,
+	deadline time.Time,
+) (int64, error) {
+	switch params.Position {
+	case PositionBeginning:
+		return low, nil
+	case PositionEnd:
+		return max(low, high-int64(params.Limit)), nil
+	case PositionOffset:
+		return min(max(low, params.Offset), high), nil
+	case PositionTimestamp:
+		offsets, err := consumer.OffsetsForTimes([]kafka.TopicPartition{{
+			Topic:     &params.Topic,
+			Partition: params.Partition,
+			Offset:    kafka.Offset(params.Timestamp),
+		}}, remainingMs(deadline))
+		if err != nil {
+			return 0, fmt.Errorf("failed to look up offsets for timestamp: %w", err)
+		}
+		if len(offsets) == 0 || offsets[0].Offset < 0 {
+			// Timestamp is newer than the last record
+			return high, nil
+		}
+		return min(max(low, int64(offsets[0].Offset)), high), nil
+	default:
+		return 0, fmt.Errorf("unknown start position '%s'", params.Position)
+	}
+}
+
+func toRecord(m *kafka.Message) Record {
+	return Record{
+		Topic:     *m.TopicPartition.Topic,
+		Partition: m.TopicPartition.Partition,
+		Offset:    m.TopicPartition.Offset,
+		Key:       m.Key,
+		Value:     m.Value,
+		Headers:   m.Headers,
+		Timestamp: m.Timestamp,
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package client
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// TopicDiffKind classifies a topic when comparing two clusters.
+type TopicDiffKind string
+
+const (
+	// OnlyLeft marks topics missing on the right cluster.
+	OnlyLeft TopicDiffKind = "missing on right"
+	// OnlyRight marks topics missing on the left cluster.
+	OnlyRight TopicDiffKind = "missing on left"
+	// Mismatch marks topics whose layout or overridden configs differ.
+	Mismatch TopicDiffKind = "mismatch"
+	// Identical marks topics that are the same on both clusters.
+	Identical TopicDiffKind = "identical"
+)
+
+// ConfigDiff is a config entry overridden differently on two clusters. An empty
+// value means the entry is not overridden on that side.
+type ConfigDiff struct {
+	Name  string
+	Left  string
+	Right string
+}
+
+// TopicDiff compares a topic on two clusters, Left or Right is nil when the topic
+// is missing on that side.
+type TopicDiff struct {
+	Topic  string
+	Kind   TopicDiffKind
+	Left   *TopicSnapshot
+	Right  *TopicSnapshot
+	Config []ConfigDiff
+}
+
+// PartitionsDiffer reports whether the topic has a different partition count.
+func (d TopicDiff) PartitionsDiffer() bool {
+	return d.Left != nil && d.Right != nil && len(d.Left.Partitions) != len(d.Right.Partitions)
+}
+
+// ReplicationFactorDiffers reports whether the topic has a different replication factor.
+func (d TopicDiff) ReplicationFactorDiffers() bool {
+	return d.Left != nil && d.Right != nil && d.Left.ReplicationFactor != d.Right.ReplicationFactor
+}
+
+// TopicLayout describes every topic of the cluster with its partitions and the
+// configs overridden on the topic.
+func (client *Client) TopicLayout(resultChan chan<- []TopicSnapshot, errorChan chan<- error) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+		defer cancel()
+
+		s := &Snapshot{}
+		if err := client.snapshotTopics(ctx, s); err != nil {
+			errorChan <- err
+			return
+		}
+		resultChan <- s.Topics
+	}()
+}
+
+// DiffTopics compares the topics of two clusters by name. Internal topics and
+// topics prefixed with '_' are skipped. The result is sorted by topic name.
+func DiffTopics(left, right []TopicSnapshot) []TopicDiff {
+	byName := make(map[string]*TopicDiff)
+	add := func(topics []TopicSnapshot, isLeft bool) {
+		for i := range topics {
+			t := &topics[i]
+			if t.Internal || strings.HasPrefix(t.Name, "_") {
+				continue
+			}
+			d, ok := byName[t.Name]
+			if !ok {
+				d = &TopicDiff{Topic: t.Name}
+				byName[t.Name] = d
+			}
+			if isLeft {
+				d.Left = t
+			} else {
+				d.Right = t
+			}
+		}
+	}
+	add(left, true)
+	add(right, false)
+
+	diffs := make([]TopicDiff, 0, len(byName))
+	for _, d := range byName {
+		switch {
+		case d.Right == nil:
+			d.Kind = OnlyLeft
+		case d.Left == nil:
+			d.Kind = OnlyRight
+		default:
+			d.Config = diffConfig(d.Left.Config, d.Right.Config)
+			d.Kind = Identical
+			if d.PartitionsDiffer() || d.ReplicationFactorDiffers() || len(d.Config) > 0 {
+				d.Kind = Mismatch
+			}
+		}
+		diffs = append(diffs, *d)
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Topic < diffs[j].Topic })
+	return diffs
+}
+
+func diffConfig(left, right map[string]string) []ConfigDiff {
+	names := make(map[string]struct{}, len(left)+len(right))
+	for name := range left {
+		names[name] = struct{}{}
+	}
+	for name := range right {
+		names[name] = struct{}{}
+	}
+
+	var diffs []ConfigDiff
+	for name := range names {
+		if left[name] != right[name] {
+			diffs = append(diffs, ConfigDiff{Name: name, Left: left[name], Right: right[name]})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Name < diffs[j].Name })
+	return diffs
+}
@@ -0,0 +1,216 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/rs/zerolog/log"
+)
+
+// HealthIssue is a problem of a partition detected by the health check.
+type HealthIssue string
+
+const (
+	// IssueOffline marks partitions without a leader.
+	IssueOffline HealthIssue = "offline"
+	// IssueUnderReplicated marks partitions with fewer in-sync replicas than replicas.
+	IssueUnderReplicated HealthIssue = "under-replicated"
+	// IssueAtMinISR marks partitions whose in-sync replicas are at or below
+	// min.insync.replicas, one more failure makes them reject acks=all writes.
+	IssueAtMinISR HealthIssue = "at min ISR"
+	// IssueNonPreferredLeader marks partitions not led by their preferred replica.
+	IssueNonPreferredLeader HealthIssue = "non-preferred leader"
+)
+
+// NoLeader is the leader of an offline partition.
+const NoLeader = -1
+
+// PartitionHealth describes an unhealthy partition.
+type PartitionHealth struct {
+	TopicPartition
+	Leader   int
+	Replicas []int
+	ISR      []int
+	// MinISR is the min.insync.replicas of the topic, 0 when unknown.
+	MinISR int
+	Issues []HealthIssue
+	// Brokers are the brokers the issues are attributed to: replicas out of
+	// sync, all replicas of offline partitions, and the current and preferred
+	// leader of non-preferred leaders.
+	Brokers []int
+}
+
+// ClusterHealth contains the unhealthy partitions of a cluster.
+type ClusterHealth struct {
+	Brokers    []kafka.BrokerMetadata
+	Topics     int
+	Total      int
+	Partitions []PartitionHealth
+}
+
+// ByBroker groups the unhealthy partitions by the brokers their issues are attributed to.
+func (h *ClusterHealth) ByBroker() map[int][]PartitionHealth {
+	grouped := make(map[int][]PartitionHealth)
+	for _, p := range h.Partitions {
+		for _, b := range p.Brokers {
+			grouped[b] = append(grouped[b], p)
+		}
+	}
+	return grouped
+}
+
+// ClusterHealth describes all topics of the cluster and reports under-replicated,
+// offline, at min ISR and non-preferred leader partitions.
+func (client *Client) ClusterHealth(
+	resultChan chan<- *ClusterHealth,
+	errorChan chan<- error,
+) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+		defer cancel()
+
+		metadata, err := client.GetMetadata(nil, true, int(client.Timeout.Milliseconds()))
+		if err != nil {
+			errorChan <- err
+			return
+		}
+
+		health := &ClusterHealth{Brokers: metadata.Brokers}
+		sort.Slice(health.Brokers, func(i, j int) bool {
+			return health.Brokers[i].ID < health.Brokers[j].ID
+		})
+
+		names := make([]string, 0, len(metadata.Topics))
+		for name := range metadata.Topics {
+			names = append(names, name)
+		}
+		if len(names) == 0 {
+			resultChan <- health
+			return
+		}
+
+		desc, err := client.DescribeTopics(
+			ctx,
+			kafka.NewTopicCollectionOfTopicNames(names),
+			kafka.SetAdminRequestTimeout(client.Timeout),
+		)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+
+		minISR := client.minInSyncReplicas(ctx, names)
+		for _, d := range desc.TopicDescriptions {
+			if d.Error.Code() != kafka.ErrNoError {
+				errorChan <- fmt.Errorf("failed to describe topic '%s': %s", d.Name, d.Error.String())
+				return
+			}
+			health.Topics++
+			for _, p := range d.Partitions {
+				health.Total++
+				if ph, ok := checkPartition(d.Name, p, minISR[d.Name]); ok {
+					health.Partitions = append(health.Partitions, ph)
+				}
+			}
+		}
+
+		sort.Slice(health.Partitions, func(i, j int) bool {
+			a, b := health.Partitions[i], health.Partitions[j]
+			if a.Topic == b.Topic {
+				return a.Partition < b.Partition
+			}
+			return a.Topic < b.Topic
+		})
+		resultChan <- health
+	}()
+}
+
+// checkPartition returns the health of a partition and whether it has any issue.
+func checkPartition(topic string, p kafka.TopicPartitionInfo, minISR int) (PartitionHealth, bool) {
+	ph := PartitionHealth{
+		TopicPartition: TopicPartition{topic, int32(p.Partition)},
+		Leader:         NoLeader,
+		MinISR:         minISR,
+	}
+	if p.Leader != nil && p.Leader.ID >= 0 {
+		ph.Leader = p.Leader.ID
+	}
+	for _, r := range p.Replicas {
+		ph.Replicas = append(ph.Replicas, r.ID)
+	}
+	for _, r := range p.Isr {
+		ph.ISR = append(ph.ISR, r.ID)
+	}
+
+	var outOfSync []int
+	for _, r := range ph.Replicas {
+		if !slices.Contains(ph.ISR, r) {
+			outOfSync = append(outOfSync, r)
+		}
+	}
+
+	attribute := func(brokers ...int) {
+		for _, b := range brokers {
+			if b != NoLeader && !slices.Contains(ph.Brokers, b) {
+				ph.Brokers = append(ph.Brokers, b)
+			}
+		}
+	}
+
+	if ph.Leader == NoLeader {
+		ph.Issues = append(ph.Issues, IssueOffline)
+		attribute(ph.Replicas...)
+	}
+	if len(outOfSync) > 0 {
+		ph.Issues = append(ph.Issues, IssueUnderReplicated)
+		attribute(outOfSync...)
+	}
+	if minISR > 0 && len(ph.ISR) <= minISR && ph.Leader != NoLeader {
+		ph.Issues = append(ph.Issues, IssueAtMinISR)
+		if len(outOfSync) == 0 {
+			attribute(ph.Leader)
+		}
+	}
+	if ph.Leader != NoLeader && len(ph.Replicas) > 0 && ph.Leader != ph.Replicas[0] {
+		ph.Issues = append(ph.Issues, IssueNonPreferredLeader)
+		attribute(ph.Leader, ph.Replicas[0])
+	}
+
+	sort.Ints(ph.Brokers)
+	return ph, len(ph.Issues) > 0
+}
+
+// minInSyncReplicas returns min.insync.replicas per topic. Topics whose config
+// cannot be described are missing from the result and skip the min ISR check.
+func (client *Client) minInSyncReplicas(ctx context.Context, topics []string) map[string]int {
+	resources := make([]kafka.ConfigResource, len(topics))
+	for i, name := range topics {
+		resources[i] = kafka.ConfigResource{Type: kafka.ResourceTopic, Name: name}
+	}
+
+	result := make(map[string]int, len(topics))
+	results, err := client.DescribeConfigs(ctx, resources, kafka.SetAdminRequestTimeout(client.Timeout))
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to describe min.insync.replicas of topics")
+		return result
+	}
+	for _, r := range results {
+		if r.Error.Code() != kafka.ErrNoError {
+			continue
+		}
+		if entry, ok := r.Config["min.insync.replicas"]; ok {
+			if n, err := strconv.Atoi(entry.Value); err == nil {
+				result[r.Name] = n
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,140 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package client
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"golang.org/x/exp/maps"
+)
+
+// LagOverviewWorkers bounds the number of groups whose offsets are fetched concurrently.
+const LagOverviewWorkers = 8
+
+// GroupLag summarizes the lag of a consumer group across all its committed partitions.
+type GroupLag struct {
+	Group   string
+	State   kafka.ConsumerGroupState
+	Members int
+	Topics  int
+	// TotalLag is the sum of the lag of all committed partitions.
+	TotalLag int64
+	// MaxLag is the largest lag of a single partition.
+	MaxLag int64
+	// Error is set when the offsets of the group could not be fetched.
+	Error error
+}
+
+// LagOverview fetches the lag of every consumer group of the cluster using at most
+// LagOverviewWorkers concurrent workers. Groups whose offsets could not be fetched
+// before the timeout are returned with Error set. The result is sorted by total lag.
+func (client *Client) LagOverview(
+	resultChan chan<- []GroupLag,
+	errorChan chan<- error,
+) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+		defer cancel()
+
+		listed, err := client.ListConsumerGroups(ctx)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		if len(listed.Valid) == 0 {
+			resultChan <- nil
+			return
+		}
+
+		lags := make([]GroupLag, len(listed.Valid))
+		ids := make([]string, len(listed.Valid))
+		index := make(map[string]int, len(listed.Valid))
+		for i, g := range listed.Valid {
+			lags[i] = GroupLag{Group: g.GroupID, State: g.State}
+			ids[i] = g.GroupID
+			index[g.GroupID] = i
+		}
+
+		described, err := client.DescribeConsumerGroups(ctx, ids)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		for _, d := range described.ConsumerGroupDescriptions {
+			if i, ok := index[d.GroupID]; ok && d.Error.Code() == kafka.ErrNoError {
+				lags[i].State = d.State
+				lags[i].Members = len(d.Members)
+			}
+		}
+
+		jobs := make(chan int)
+		var wg sync.WaitGroup
+		for range min(LagOverviewWorkers, len(lags)) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					client.groupLag(ctx, &lags[i])
+				}
+			}()
+		}
+		for i := range lags {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+
+		sort.SliceStable(lags, func(i, j int) bool { return lags[i].TotalLag > lags[j].TotalLag })
+		resultChan <- lags
+	}()
+}
+
+// groupLag fills the lag of a group from its committed offsets and the log end offsets.
+// Partitions without a committed offset do not contribute to the lag.
+func (client *Client) groupLag(ctx context.Context, lag *GroupLag) {
+	errs := make(chan error, 1)
+	result := &DescribeConsumerGroupResult{}
+
+	client.CurrentOffsets(ctx, lag.Group, errs, result)
+	select {
+	case err := <-errs:
+		lag.Error = err
+		return
+	default:
+	}
+
+	committed := make(map[TopicPartition]kafka.Offset)
+	topics := make(map[string]struct{})
+	for tp, offset := range result.currentOffsets {
+		if offset >= 0 {
+			committed[tp] = offset
+			topics[tp.Topic] = struct{}{}
+		}
+	}
+	lag.Topics = len(topics)
+	if len(committed) == 0 {
+		return
+	}
+
+	client.LogEndOffsets(ctx, maps.Keys(committed), errs, result)
+	select {
+	case err := <-errs:
+		lag.Error = err
+		return
+	default:
+	}
+
+	result.SetLag(committed, result.logEndOffsets)
+	for _, l := range result.lag {
+		if l < 0 {
+			continue
+		}
+		lag.TotalLag += int64(l)
+		lag.MaxLag = max(lag.MaxLag, int64(l))
+	}
+}
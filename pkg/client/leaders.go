@@ -0,0 +1,165 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+// AnyBroker disables filtering of imbalanced partitions by broker.
+const AnyBroker int32 = -1
+
+// ElectLeadersParams describes the partitions a leader election runs for.
+type ElectLeadersParams struct {
+	Type kafka.ElectionType
+	// Topic limits the election to a single topic, empty means all partitions
+	// of the cluster whose leader is not the preferred replica.
+	Topic string
+	// Partitions limits the election to the given partitions of Topic,
+	// empty means all partitions.
+	Partitions []int32
+	// Broker limits the imbalanced partitions to those led by or preferring
+	// the broker, AnyBroker means all brokers. Ignored when Topic is set.
+	Broker int32
+}
+
+// Election contains the outcome of a leader election of a single partition.
+type Election struct {
+	TopicPartition
+	// Leader is the leader before the election.
+	Leader    int32
+	Preferred int32
+	Error     error
+}
+
+// ElectLeadersResult contains per-partition outcomes of a leader election.
+type ElectLeadersResult struct {
+	Params    ElectLeadersParams
+	Elections []Election
+}
+
+// ElectLeaders runs a preferred or unclean leader election.
+func (client *Client) ElectLeaders(
+	params ElectLeadersParams,
+	resultChan chan<- *ElectLeadersResult,
+	errorChan chan<- error,
+) {
+	resultChan, errorChan = audited(client.ClusterName, "partition_leaders", "elect",
+		electionParams(params), resultChan, errorChan)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+		defer cancel()
+
+		result := &ElectLeadersResult{Params: params}
+		elections, err := client.electionScope(params)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		// An empty partition list elects leaders of every partition of the
+		// cluster, so there is nothing to request when nothing is imbalanced.
+		if len(elections) == 0 {
+			resultChan <- result
+			return
+		}
+
+		index := make(map[TopicPartition]int)
+		partitions := make([]kafka.TopicPartition, 0, len(elections))
+		for i, e := range elections {
+			index[e.TopicPartition] = i
+			partitions = append(partitions, kafka.TopicPartition{
+				Topic:     &elections[i].Topic,
+				Partition: e.Partition,
+			})
+		}
+
+		elected, err := client.AdminClient.ElectLeaders(
+			ctx,
+			kafka.NewElectLeadersRequest(params.Type, partitions),
+			kafka.SetAdminRequestTimeout(client.Timeout),
+		)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		for _, tp := range elected.TopicPartitions {
+			if i, ok := index[TopicPartition{*tp.Topic, tp.Partition}]; ok {
+				elections[i].Error = tp.Error
+			}
+		}
+
+		result.Elections = elections
+		resultChan <- result
+	}()
+}
+
+// electionScope resolves the partitions of an election together with their current leaders.
+func (client *Client) electionScope(params ElectLeadersParams) ([]Election, error) {
+	var topic *string
+	if params.Topic != "" {
+		topic = &params.Topic
+	}
+	metadata, err := client.GetMetadata(topic, topic == nil, int(client.Timeout.Milliseconds()))
+	if err != nil {
+		return nil, err
+	}
+
+	var elections []Election
+	for name, tm := range metadata.Topics {
+		if tm.Error.Code() != kafka.ErrNoError {
+			if topic != nil {
+				return nil, fmt.Errorf("failed to describe topic '%s': %s", name, tm.Error.String())
+			}
+			continue
+		}
+
+		for _, p := range tm.Partitions {
+			if len(p.Replicas) == 0 {
+				continue
+			}
+			e := Election{
+				TopicPartition: TopicPartition{name, p.ID},
+				Leader:         p.Leader,
+				Preferred:      p.Replicas[0],
+			}
+
+			if topic != nil {
+				if len(params.Partitions) == 0 || slices.Contains(params.Partitions, p.ID) {
+					elections = append(elections, e)
+				}
+				continue
+			}
+
+			if e.Leader == e.Preferred {
+				continue
+			}
+			if params.Broker == AnyBroker || e.Leader == params.Broker || e.Preferred == params.Broker {
+				elections = append(elections, e)
+			}
+		}
+	}
+
+	if topic != nil {
+		for _, p := range params.Partitions {
+			if !slices.ContainsFunc(elections, func(e Election) bool { return e.Partition == p }) {
+				return nil, fmt.Errorf("topic '%s' has no partition %d", params.Topic, p)
+			}
+		}
+	}
+
+	sort.Slice(elections, func(i, j int) bool {
+		if elections[i].Topic == elections[j].Topic {
+			return elections[i].Partition < elections[j].Partition
+		}
+		return elections[i].Topic < elections[j].Topic
+	})
+	return elections, nil
+}
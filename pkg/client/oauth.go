@@ -0,0 +1,338 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/config"
+)
+
+const (
+	// DefaultTokenLifetime is the lifetime of tokens telling neither their
+	// expiration nor their lifetime.
+	DefaultTokenLifetime = time.Hour
+	// TokenRetryInterval is the delay before refreshing again after a failed
+	// refresh, for clients that do not receive refresh events while idle.
+	TokenRetryInterval = 10 * time.Second
+	// DefaultPrincipal is the principal of tokens when none is known.
+	DefaultPrincipal = "cinnamon"
+)
+
+// Token is the outcome of the last token refresh of a client.
+type Token struct {
+	// Principal and Expiration describe the last token obtained.
+	Principal  string
+	Expiration time.Time
+	// Refreshed is the time of the last refresh.
+	Refreshed time.Time
+	// Err is why the last refresh failed.
+	Err error
+}
+
+// tokenResponse is the answer of a token endpoint, also accepted from commands.
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// tokenHandle is a librdkafka handle SASL/OAUTHBEARER tokens are set on.
+type tokenHandle interface {
+	SetOAuthBearerToken(token kafka.OAuthBearerToken) error
+	SetOAuthBearerTokenFailure(errstr string) error
+}
+
+// tokenProvider obtains the tokens of a cluster and remembers the last one.
+type tokenProvider struct {
+	cluster string
+	config  *config.OAuthConfig
+	timeout time.Duration
+	mx      sync.RWMutex
+	token   Token
+}
+
+// newTokenProvider returns the token provider of a cluster, nil when the cluster
+// has no OAuth config and librdkafka handles its authentication alone.
+func newTokenProvider(cluster *config.ClusterConfig, timeout time.Duration) (*tokenProvider, error) {
+	if cluster.OAuth == nil {
+		return nil, nil
+	}
+	mechanism, ok := cluster.Properties["sasl.mechanism"]
+	if !ok {
+		mechanism = cluster.Properties["sasl.mechanisms"]
+	}
+	if !strings.EqualFold(mechanism, "OAUTHBEARER") {
+		return nil, fmt.Errorf(
+			"oauth of cluster '%s' requires sasl.mechanism OAUTHBEARER",
+			cluster.Name,
+		)
+	}
+	if (cluster.OAuth.TokenEndpoint == "") == (cluster.OAuth.Command == "") {
+		return nil, fmt.Errorf(
+			"oauth of cluster '%s' requires either a tokenEndpoint or a command",
+			cluster.Name,
+		)
+	}
+
+	return &tokenProvider{
+		cluster: cluster.Name,
+		config:  cluster.OAuth,
+		timeout: timeout,
+	}, nil
+}
+
+// refresh obtains a new token and sets it on the handle, or tells the handle
+// why no token could be obtained.
+func (p *tokenProvider) refresh(handle tokenHandle) {
+	token, err := FetchToken(p.cluster, p.config, p.timeout)
+	if err == nil {
+		err = handle.SetOAuthBearerToken(token)
+	}
+
+	p.mx.Lock()
+	p.token.Refreshed = time.Now()
+	p.token.Err = err
+	if err == nil {
+		p.token.Principal = token.Principal
+		p.token.Expiration = token.Expiration
+	}
+	p.mx.Unlock()
+
+	if err != nil {
+		log.Error().Err(err).Str("cluster", p.cluster).Msg("failed to refresh OAuth token")
+		_ = handle.SetOAuthBearerTokenFailure(err.Error())
+		return
+	}
+	log.Info().
+		Str("cluster", p.cluster).
+		Time("expiration", token.Expiration).
+		Msg("refreshed OAuth token")
+}
+
+// keepRefreshed refreshes the token of a handle in the background ahead of its
+// expiration, for handles that receive refresh events only while polled. The
+// returned function requests an immediate refresh and never blocks.
+func (p *tokenProvider) keepRefreshed(handle tokenHandle) func() {
+	requests := make(chan struct{}, 1)
+	go func() {
+		for {
+			p.refresh(handle)
+			select {
+			case <-requests:
+			case <-time.After(p.refreshIn()):
+			}
+		}
+	}()
+
+	return func() {
+		select {
+		case requests <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// refreshIn returns the delay before the next background refresh: 80% of the
+// remaining lifetime of the token, as librdkafka does, or TokenRetryInterval
+// after a failed refresh.
+func (p *tokenProvider) refreshIn() time.Duration {
+	token := p.Token()
+	if token.Err != nil {
+		return TokenRetryInterval
+	}
+	return max(time.Until(token.Expiration)*4/5, time.Second)
+}
+
+// Token returns the outcome of the last refresh.
+func (p *tokenProvider) Token() Token {
+	p.mx.RLock()
+	defer p.mx.RUnlock()
+	return p.token
+}
+
+// FetchToken obtains a SASL/OAUTHBEARER token from the token endpoint or the
+// command of an OAuth config. The expiration is taken from the expires_in of the
+// response, then from the exp claim of a JWT, and defaults to DefaultTokenLifetime.
+func FetchToken(
+	cluster string,
+	oauth *config.OAuthConfig,
+	timeout time.Duration,
+) (kafka.OAuthBearerToken, error) {
+	resolved, err := oauth.Resolved(cluster)
+	if err != nil {
+		return kafka.OAuthBearerToken{}, err
+	}
+
+	var response *tokenResponse
+	if resolved.Command != "" {
+		response, err = commandToken(resolved.Command)
+	} else {
+		response, err = endpointToken(resolved, timeout)
+	}
+	if err != nil {
+		return kafka.OAuthBearerToken{}, err
+	}
+	if response.AccessToken == "" {
+		return kafka.OAuthBearerToken{}, errors.New("no access token in the response")
+	}
+
+	now := time.Now()
+	claims := jwtClaims(response.AccessToken)
+	expiration := now.Add(DefaultTokenLifetime)
+	switch {
+	case response.ExpiresIn > 0:
+		expiration = now.Add(time.Duration(response.ExpiresIn) * time.Second)
+	case claims.Exp > 0:
+		expiration = time.Unix(claims.Exp, 0)
+	}
+	if !expiration.After(now) {
+		return kafka.OAuthBearerToken{}, fmt.Errorf("token expired at %s", expiration.Format(time.RFC3339))
+	}
+
+	principal := DefaultPrincipal
+	for _, p := range []string{resolved.Principal, claims.Sub, resolved.ClientID} {
+		if p != "" {
+			principal = p
+			break
+		}
+	}
+
+	return kafka.OAuthBearerToken{
+		TokenValue: response.AccessToken,
+		Expiration: expiration,
+		Principal:  principal,
+		Extensions: resolved.Extensions,
+	}, nil
+}
+
+// commandToken runs a command printing either a token or a token endpoint response.
+func commandToken(command string) (*tokenResponse, error) {
+	output, err := config.RunCommand(command)
+	if err != nil {
+		return nil, fmt.Errorf("token command failed: %w", err)
+	}
+
+	output = strings.TrimSpace(output)
+	if !strings.HasPrefix(output, "{") {
+		return &tokenResponse{AccessToken: output}, nil
+	}
+
+	response := &tokenResponse{}
+	if err := json.Unmarshal([]byte(output), response); err != nil {
+		return nil, fmt.Errorf("failed to parse token command output: %w", err)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("token command failed: %s", response.describeError())
+	}
+	return response, nil
+}
+
+// endpointToken requests a token from an OIDC token endpoint with the client
+// credentials grant, the client authenticating with HTTP basic authentication.
+func endpointToken(oauth *config.OAuthConfig, timeout time.Duration) (*tokenResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if oauth.Scope != "" {
+		form.Set("scope", oauth.Scope)
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		oauth.TokenEndpoint,
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token endpoint: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(oauth.ClientID), url.QueryEscape(oauth.ClientSecret))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("token endpoint did not answer within %s", timeout)
+		}
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	response := &tokenResponse{}
+	decodeErr := json.Unmarshal(body, response)
+	if resp.StatusCode != http.StatusOK {
+		if decodeErr == nil && response.Error != "" {
+			return nil, fmt.Errorf(
+				"token endpoint answered %s: %s",
+				resp.Status,
+				response.describeError(),
+			)
+		}
+		return nil, fmt.Errorf("token endpoint answered %s", resp.Status)
+	}
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to parse token response: %w", decodeErr)
+	}
+	return response, nil
+}
+
+func (r *tokenResponse) describeError() string {
+	if r.ErrorDescription != "" {
+		return r.Error + ": " + r.ErrorDescription
+	}
+	return r.Error
+}
+
+// claims are the registered JWT claims used to describe a token.
+type claims struct {
+	Sub string `json:"sub"`
+	Exp int64  `json:"exp"`
+}
+
+// jwtClaims decodes the claims of a JWT without verifying it, the broker does.
+// Opaque tokens have no claims.
+func jwtClaims(token string) claims {
+	var c claims
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return c
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return c
+	}
+	_ = json.Unmarshal(payload, &c)
+	return c
+}
+
+// Token returns the outcome of the last token refresh, false when the tokens
+// of the cluster are not obtained by cinnamon.
+func (client *Client) Token() (Token, bool) {
+	if client.tokens == nil {
+		return Token{}, false
+	}
+	return client.tokens.Token(), true
+}
@@ -0,0 +1,190 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package client
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/uraniumdawn/cinnamon/pkg/config"
+)
+
+const testTimeout = 5 * time.Second
+
+// jwt builds an unsigned JWT carrying the given claims.
+func jwt(claims string) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	return encode([]byte(`{"alg":"none"}`)) + "." + encode([]byte(claims)) + ".signature"
+}
+
+// tokenEndpoint starts a stub token endpoint answering every request with
+// status and body, after checking the client credentials grant.
+func tokenEndpoint(t *testing.T, status int, body string) *config.OAuthConfig {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("failed to parse token request: %s", err)
+		}
+		if grant := r.PostForm.Get("grant_type"); grant != "client_credentials" {
+			t.Errorf("grant_type = %q, want client_credentials", grant)
+		}
+		if scope := r.PostForm.Get("scope"); scope != "kafka" {
+			t.Errorf("scope = %q, want kafka", scope)
+		}
+		if id, secret, ok := r.BasicAuth(); !ok || id != "cinnamon" || secret != "s3cret" {
+			t.Errorf("basic auth = %q:%q, want cinnamon:s3cret", id, secret)
+		}
+		w.WriteHeader(status)
+		_, _ = fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	return &config.OAuthConfig{
+		TokenEndpoint: server.URL,
+		ClientID:      "cinnamon",
+		ClientSecret:  "s3cret",
+		Scope:         "kafka",
+	}
+}
+
+func TestFetchTokenFromEndpoint(t *testing.T) {
+	oauth := tokenEndpoint(t, http.StatusOK, `{"access_token":"opaque","expires_in":120}`)
+
+	before := time.Now()
+	token, err := FetchToken("test", oauth, testTimeout)
+	if err != nil {
+		t.Fatalf("FetchToken() error = %s", err)
+	}
+	if token.TokenValue != "opaque" {
+		t.Errorf("TokenValue = %q, want opaque", token.TokenValue)
+	}
+	if token.Principal != "cinnamon" {
+		t.Errorf("Principal = %q, want the client ID", token.Principal)
+	}
+	if lifetime := token.Expiration.Sub(before); lifetime < 119*time.Second || lifetime > 121*time.Second {
+		t.Errorf("Expiration is %s after the request, want expires_in 120s", lifetime)
+	}
+}
+
+func TestFetchTokenJWTExpiration(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	access := jwt(fmt.Sprintf(`{"sub":"alice","exp":%d}`, exp.Unix()))
+	oauth := tokenEndpoint(t, http.StatusOK, fmt.Sprintf(`{"access_token":%q}`, access))
+
+	token, err := FetchToken("test", oauth, testTimeout)
+	if err != nil {
+		t.Fatalf("FetchToken() error = %s", err)
+	}
+	if !token.Expiration.Equal(exp) {
+		t.Errorf("Expiration = %s, want the exp claim %s", token.Expiration, exp)
+	}
+	if token.Principal != "alice" {
+		t.Errorf("Principal = %q, want the sub claim alice", token.Principal)
+	}
+}
+
+func TestFetchTokenEndpointErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{
+			name:   "error description",
+			status: http.StatusUnauthorized,
+			body:   `{"error":"invalid_client","error_description":"bad secret"}`,
+			want:   "token endpoint answered 401 Unauthorized: invalid_client: bad secret",
+		},
+		{
+			name:   "non-200 status",
+			status: http.StatusServiceUnavailable,
+			body:   "<html>maintenance</html>",
+			want:   "token endpoint answered 503 Service Unavailable",
+		},
+		{
+			name:   "no access token",
+			status: http.StatusOK,
+			body:   `{"expires_in":120}`,
+			want:   "no access token in the response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oauth := tokenEndpoint(t, tt.status, tt.body)
+			_, err := FetchToken("test", oauth, testTimeout)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("FetchToken() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchTokenFromCommand(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	tests := []struct {
+		name      string
+		command   string
+		value     string
+		principal string
+		lifetime  time.Duration
+	}{
+		{
+			name:      "plain token",
+			command:   "echo opaque",
+			value:     "opaque",
+			principal: DefaultPrincipal,
+			lifetime:  DefaultTokenLifetime,
+		},
+		{
+			name:      "json response",
+			command:   `echo '{"access_token":"opaque","expires_in":300}'`,
+			value:     "opaque",
+			principal: DefaultPrincipal,
+			lifetime:  300 * time.Second,
+		},
+		{
+			name:      "plain jwt",
+			command:   "echo " + jwt(fmt.Sprintf(`{"sub":"bob","exp":%d}`, exp.Unix())),
+			value:     jwt(fmt.Sprintf(`{"sub":"bob","exp":%d}`, exp.Unix())),
+			principal: "bob",
+			lifetime:  time.Until(exp),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := time.Now()
+			token, err := FetchToken("test", &config.OAuthConfig{Command: tt.command}, testTimeout)
+			if err != nil {
+				t.Fatalf("FetchToken() error = %s", err)
+			}
+			if token.TokenValue != tt.value {
+				t.Errorf("TokenValue = %q, want %q", token.TokenValue, tt.value)
+			}
+			if token.Principal != tt.principal {
+				t.Errorf("Principal = %q, want %q", token.Principal, tt.principal)
+			}
+			if diff := token.Expiration.Sub(before) - tt.lifetime; diff < -2*time.Second || diff > 2*time.Second {
+				t.Errorf("Expiration is %s after the call, want %s", token.Expiration.Sub(before), tt.lifetime)
+			}
+		})
+	}
+}
+
+func TestFetchTokenCommandError(t *testing.T) {
+	_, err := FetchToken("test", &config.OAuthConfig{
+		Command: `echo '{"error":"login_required","error_description":"run sso login"}'`,
+	}, testTimeout)
+	if err == nil || !strings.Contains(err.Error(), "login_required: run sso login") {
+		t.Errorf("FetchToken() error = %v, want the error of the command output", err)
+	}
+}
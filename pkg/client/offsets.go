@@ -0,0 +1,365 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"github.com/uraniumdawn/cinnamon/pkg/audit"
+)
+
+// ResetStrategy defines how new committed offsets of a consumer group are computed.
+type ResetStrategy string
+
+const (
+	// ResetToEarliest moves offsets to the low watermark.
+	ResetToEarliest ResetStrategy = "to-earliest"
+	// ResetToLatest moves offsets to the high watermark.
+	ResetToLatest ResetStrategy = "to-latest"
+	// ResetToTimestamp moves offsets to the first record at or after a timestamp.
+	ResetToTimestamp ResetStrategy = "to-timestamp"
+	// ResetToOffset moves offsets to an explicit offset.
+	ResetToOffset ResetStrategy = "to-offset"
+	// ResetShiftBy moves offsets by N records, negative N moves them back.
+	ResetShiftBy ResetStrategy = "shift-by"
+)
+
+// ResetStrategies lists all supported reset strategies.
+var ResetStrategies = []ResetStrategy{
+	ResetToEarliest,
+	ResetToLatest,
+	ResetToTimestamp,
+	ResetToOffset,
+	ResetShiftBy,
+}
+
+// ResetOffsetsParams describes an offset reset of a consumer group.
+type ResetOffsetsParams struct {
+	Group    string
+	Strategy ResetStrategy
+	// Topic limits the reset to a single topic, empty means all topics
+	// the group has committed offsets for.
+	Topic string
+	// Partitions limits the reset to the given partitions of Topic,
+	// empty means all partitions.
+	Partitions []int32
+	// Value is the offset for ResetToOffset, the shift for ResetShiftBy and
+	// the timestamp in milliseconds since epoch for ResetToTimestamp.
+	Value int64
+}
+
+// OffsetReset contains the current and the new committed offset of a partition.
+type OffsetReset struct {
+	TopicPartition
+	// Current is kafka.OffsetInvalid when the group has no committed offset.
+	Current kafka.Offset
+	New     kafka.Offset
+	Low     kafka.Offset
+	High    kafka.Offset
+}
+
+// Lag returns the lag of the partition after the reset.
+func (r OffsetReset) Lag() int64 {
+	return int64(r.High - r.New)
+}
+
+// ResetOffsetsPlan is the dry-run result of an offset reset.
+type ResetOffsetsPlan struct {
+	Params ResetOffsetsParams
+	State  kafka.ConsumerGroupState
+	Resets []OffsetReset
+}
+
+// Applicable reports whether the group state allows committing the plan.
+func (p *ResetOffsetsPlan) Applicable() bool {
+	return p.State == kafka.ConsumerGroupStateEmpty
+}
+
+// PlanOffsetReset computes new offsets of a consumer group without committing them.
+func (client *Client) PlanOffsetReset(
+	params ResetOffsetsParams,
+	resultChan chan<- *ResetOffsetsPlan,
+	errorChan chan<- error,
+) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+		defer cancel()
+
+		plan, err := client.planOffsetReset(ctx, params)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		resultChan <- plan
+	}()
+}
+
+// ResetOffsets commits the new offsets of a plan. The group state is checked
+// again right before committing and the reset is refused unless the group is Empty.
+func (client *Client) ResetOffsets(
+	plan *ResetOffsetsPlan,
+	resultChan chan<- bool,
+	errorChan chan<- error,
+) {
+	resultChan, errorChan = audited(client.ClusterName, "consumer_group", "reset_offsets", audit.Params{
+		"group":    plan.Params.Group,
+		"strategy": plan.Params.Strategy,
+		"topic":    plan.Params.Topic,
+		"value":    plan.Params.Value,
+		"resets":   len(plan.Resets),
+	}, resultChan, errorChan)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+		defer cancel()
+
+		group := plan.Params.Group
+		state, err := client.groupState(ctx, group)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		if state != kafka.ConsumerGroupStateEmpty {
+			errorChan <- fmt.Errorf(
+				"consumer group '%s' is %s, offsets can only be reset for an Empty group",
+				group,
+				state.String(),
+			)
+			return
+		}
+
+		partitions := make([]kafka.TopicPartition, 0, len(plan.Resets))
+		for _, r := range plan.Resets {
+			partitions = append(partitions, kafka.TopicPartition{
+				Topic:     &r.Topic,
+				Partition: r.Partition,
+				Offset:    r.New,
+			})
+		}
+
+		results, err := client.AlterConsumerGroupOffsets(
+			ctx,
+			[]kafka.ConsumerGroupTopicPartitions{{Group: group, Partitions: partitions}},
+		)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		for _, r := range results.ConsumerGroupsTopicPartitions {
+			for _, tp := range r.Partitions {
+				if tp.Error != nil {
+					errorChan <- fmt.Errorf(
+						"failed to reset offset of '%s' partition %d: %w",
+						*tp.Topic,
+						tp.Partition,
+						tp.Error,
+					)
+					return
+				}
+			}
+		}
+
+		resultChan <- true
+	}()
+}
+
+func (client *Client) planOffsetReset(
+	ctx context.Context,
+	params ResetOffsetsParams,
+) (*ResetOffsetsPlan, error) {
+	state, err := client.groupState(ctx, params.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	offsets, err := client.ListConsumerGroupOffsets(
+		ctx,
+		[]kafka.ConsumerGroupTopicPartitions{{Group: params.Group}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[TopicPartition]kafka.Offset)
+	for _, tps := range offsets.ConsumerGroupsTopicPartitions {
+		for _, tp := range tps.Partitions {
+			current[TopicPartition{*tp.Topic, tp.Partition}] = tp.Offset
+		}
+	}
+
+	scope, err := client.resetScope(ctx, params, current)
+	if err != nil {
+		return nil, err
+	}
+	if len(scope) == 0 {
+		return nil, fmt.Errorf("no partitions to reset for group '%s'", params.Group)
+	}
+
+	low, err := client.listOffsets(ctx, scope, kafka.EarliestOffsetSpec)
+	if err != nil {
+		return nil, err
+	}
+	high, err := client.listOffsets(ctx, scope, kafka.LatestOffsetSpec)
+	if err != nil {
+		return nil, err
+	}
+	var byTime map[TopicPartition]kafka.Offset
+	if params.Strategy == ResetToTimestamp {
+		byTime, err = client.listOffsets(ctx, scope, kafka.NewOffsetSpecForTimestamp(params.Value))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	plan := &ResetOffsetsPlan{Params: params, State: state}
+	for _, tp := range scope {
+		r := OffsetReset{
+			TopicPartition: tp,
+			Current:        kafka.OffsetInvalid,
+			Low:            low[tp],
+			High:           high[tp],
+		}
+		if offset, ok := current[tp]; ok {
+			r.Current = offset
+		}
+
+		switch params.Strategy {
+		case ResetToEarliest:
+			r.New = r.Low
+		case ResetToLatest:
+			r.New = r.High
+		case ResetToTimestamp:
+			r.New = r.High
+			// A negative offset means no record at or after the timestamp
+			if offset, ok := byTime[tp]; ok && offset >= 0 {
+				r.New = offset
+			}
+		case ResetToOffset:
+			r.New = kafka.Offset(params.Value)
+		case ResetShiftBy:
+			base := r.Current
+			if base < 0 {
+				base = r.Low
+			}
+			r.New = base + kafka.Offset(params.Value)
+		default:
+			return nil, fmt.Errorf("unknown reset strategy '%s'", params.Strategy)
+		}
+		r.New = min(max(r.New, r.Low), r.High)
+
+		plan.Resets = append(plan.Resets, r)
+	}
+
+	return plan, nil
+}
+
+// resetScope returns the partitions an offset reset applies to.
+func (client *Client) resetScope(
+	ctx context.Context,
+	params ResetOffsetsParams,
+	current map[TopicPartition]kafka.Offset,
+) ([]TopicPartition, error) {
+	var scope []TopicPartition
+
+	if params.Topic == "" {
+		for tp := range current {
+			scope = append(scope, tp)
+		}
+	} else {
+		// The group may not have consumed the topic yet, so partitions
+		// are taken from the topic rather than from committed offsets
+		topics, err := client.DescribeTopics(
+			ctx,
+			kafka.NewTopicCollectionOfTopicNames([]string{params.Topic}),
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, desc := range topics.TopicDescriptions {
+			if desc.Error.Code() != kafka.ErrNoError {
+				return nil, fmt.Errorf("failed to describe topic '%s': %s", desc.Name, desc.Error.String())
+			}
+			existing := make(map[int32]bool)
+			for _, p := range desc.Partitions {
+				existing[int32(p.Partition)] = true
+			}
+
+			if len(params.Partitions) == 0 {
+				for p := range existing {
+					scope = append(scope, TopicPartition{desc.Name, p})
+				}
+				continue
+			}
+			for _, p := range params.Partitions {
+				if !existing[p] {
+					return nil, fmt.Errorf("topic '%s' has no partition %d", desc.Name, p)
+				}
+				scope = append(scope, TopicPartition{desc.Name, p})
+			}
+		}
+	}
+
+	sort.Slice(scope, func(i, j int) bool {
+		if scope[i].Topic == scope[j].Topic {
+			return scope[i].Partition < scope[j].Partition
+		}
+		return scope[i].Topic < scope[j].Topic
+	})
+	return scope, nil
+}
+
+func (client *Client) listOffsets(
+	ctx context.Context,
+	tps []TopicPartition,
+	spec kafka.OffsetSpec,
+) (map[TopicPartition]kafka.Offset, error) {
+	request := make(map[kafka.TopicPartition]kafka.OffsetSpec)
+	for _, tp := range tps {
+		request[kafka.TopicPartition{
+			Topic:     &tp.Topic,
+			Partition: tp.Partition,
+		}] = spec
+	}
+
+	result, err := client.ListOffsets(ctx, request,
+		kafka.SetAdminIsolationLevel(kafka.IsolationLevelReadCommitted))
+	if err != nil {
+		return nil, err
+	}
+	offsets := make(map[TopicPartition]kafka.Offset)
+	for tp, info := range result.ResultInfos {
+		if info.Error.Code() != kafka.ErrNoError {
+			return nil, fmt.Errorf(
+				"failed to list offsets of '%s' partition %d: %s",
+				*tp.Topic,
+				tp.Partition,
+				info.Error.String(),
+			)
+		}
+		offsets[TopicPartition{*tp.Topic, tp.Partition}] = info.Offset
+	}
+	return offsets, nil
+}
+
+func (client *Client) groupState(ctx context.Context, group string) (kafka.ConsumerGroupState, error) {
+	groups, err := client.DescribeConsumerGroups(ctx, []string{group})
+	if err != nil {
+		return kafka.ConsumerGroupStateUnknown, err
+	}
+	for _, desc := range groups.ConsumerGroupDescriptions {
+		if desc.Error.Code() != kafka.ErrNoError {
+			return kafka.ConsumerGroupStateUnknown, fmt.Errorf(
+				"failed to describe consumer group '%s': %s",
+				group,
+				desc.Error.String(),
+			)
+		}
+		return desc.State, nil
+	}
+	return kafka.ConsumerGroupStateUnknown, fmt.Errorf("consumer group '%s' not found", group)
+}
@@ -0,0 +1,177 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/config"
+)
+
+// DefaultPartitioner keeps the partitioner configured in the cluster properties.
+const DefaultPartitioner = "default"
+
+// Partitioners lists the partitioners supported by librdkafka.
+var Partitioners = []string{
+	DefaultPartitioner,
+	"consistent_random",
+	"murmur2_random",
+	"murmur2",
+	"consistent",
+	"fnv1a",
+	"fnv1a_random",
+	"random",
+}
+
+// ProduceParams describes records to be sent to a topic.
+type ProduceParams struct {
+	Topic   string
+	Key     []byte
+	Value   []byte
+	Headers []kafka.Header
+	// Partition is kafka.PartitionAny to let the partitioner choose.
+	Partition int32
+	Count     int
+}
+
+// DeliveryReport contains the delivery outcome of a single record.
+type DeliveryReport struct {
+	Partition int32
+	Offset    kafka.Offset
+	Error     error
+}
+
+// ProduceResult contains delivery reports for all produced records.
+type ProduceResult struct {
+	Topic   string
+	Reports []DeliveryReport
+	// Count is the number of records requested.
+	Count int
+	// EnqueueError is why the records after the reported ones were not sent.
+	EnqueueError error
+}
+
+// Producer wraps the Kafka Producer with cluster name context.
+type Producer struct {
+	ClusterName string
+	Timeout     time.Duration
+	*kafka.Producer
+}
+
+func NewProducer(
+	config *config.ClusterConfig,
+	partitioner string,
+	timeout time.Duration,
+) (*Producer, error) {
+	conf, err := newConfigMap(config)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to resolve cluster properties")
+		return nil, err
+	}
+	tokens, err := newTokenProvider(config, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if partitioner != "" && partitioner != DefaultPartitioner {
+		_ = conf.SetKey("partitioner", partitioner)
+	}
+
+	producer, err := kafka.NewProducer(conf)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to create Producer")
+		return nil, err
+	}
+
+	// Delivery reports are sent to per-call channels, everything else
+	// arriving here are token refresh requests and client level errors.
+	go func() {
+		for event := range producer.Events() {
+			switch e := event.(type) {
+			case kafka.OAuthBearerTokenRefresh:
+				if tokens != nil {
+					tokens.refresh(producer)
+				}
+			case kafka.Error:
+				log.Error().Err(e).Msg("producer error")
+			}
+		}
+	}()
+
+	return &Producer{
+		ClusterName: config.Name,
+		Timeout:     timeout,
+		Producer:    producer,
+	}, nil
+}
+
+// Produce sends params.Count copies of a record and waits for their delivery reports.
+func (producer *Producer) Produce(
+	params ProduceParams,
+	resultChan chan<- *ProduceResult,
+	errorChan chan<- error,
+) {
+	count := max(params.Count, 1)
+	resultChan, errorChan = audited(producer.ClusterName, "topic", "produce",
+		produceParams(params, count), resultChan, errorChan)
+
+	go func() {
+		deliveryChan := make(chan kafka.Event, count)
+		result := &ProduceResult{Topic: params.Topic, Count: count}
+
+		enqueued := 0
+		for ; enqueued < count; enqueued++ {
+			err := producer.Producer.Produce(&kafka.Message{
+				TopicPartition: kafka.TopicPartition{
+					Topic:     &params.Topic,
+					Partition: params.Partition,
+				},
+				Key:     params.Key,
+				Value:   params.Value,
+				Headers: params.Headers,
+			}, deliveryChan)
+			if err != nil {
+				err = fmt.Errorf("failed to enqueue record %d of %d: %w", enqueued+1, count, err)
+				if enqueued == 0 {
+					errorChan <- err
+					return
+				}
+				// The records already enqueued may be delivered, their reports are awaited.
+				result.EnqueueError = err
+				break
+			}
+		}
+
+		timer := time.NewTimer(producer.Timeout)
+		defer timer.Stop()
+
+		for len(result.Reports) < enqueued {
+			select {
+			case event := <-deliveryChan:
+				m, ok := event.(*kafka.Message)
+				if !ok {
+					continue
+				}
+				result.Reports = append(result.Reports, DeliveryReport{
+					Partition: m.TopicPartition.Partition,
+					Offset:    m.TopicPartition.Offset,
+					Error:     m.TopicPartition.Error,
+				})
+			case <-timer.C:
+				errorChan <- fmt.Errorf(
+					"%d of %d records not acknowledged in time",
+					enqueued-len(result.Reports),
+					count,
+				)
+				return
+			}
+		}
+
+		resultChan <- result
+	}()
+}
@@ -0,0 +1,155 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+// DefaultScramIterations is the iteration count used when none is given,
+// it is also the minimum accepted by the brokers.
+const DefaultScramIterations = 4096
+
+// ScramMechanisms lists the supported SASL/SCRAM mechanisms.
+var ScramMechanisms = []kafka.ScramMechanism{
+	kafka.ScramMechanismSHA256,
+	kafka.ScramMechanismSHA512,
+}
+
+// ScramCredential is a single SASL/SCRAM credential of a user.
+type ScramCredential struct {
+	User string
+	kafka.ScramCredentialInfo
+}
+
+// ScramCredentials lists the SASL/SCRAM credentials of all users, sorted by user and mechanism.
+func (client *Client) ScramCredentials(
+	resultChan chan<- []ScramCredential,
+	errorChan chan<- error,
+) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+		defer cancel()
+
+		result, err := client.DescribeUserScramCredentials(
+			ctx,
+			nil,
+			kafka.SetAdminRequestTimeout(client.Timeout),
+		)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+
+		var credentials []ScramCredential
+		for user, description := range result.Descriptions {
+			if description.Error.Code() != kafka.ErrNoError {
+				errorChan <- fmt.Errorf(
+					"failed to describe credentials of user '%s': %s",
+					user,
+					description.Error.String(),
+				)
+				return
+			}
+			for _, info := range description.ScramCredentialInfos {
+				credentials = append(credentials, ScramCredential{User: user, ScramCredentialInfo: info})
+			}
+		}
+
+		sort.Slice(credentials, func(i, j int) bool {
+			if credentials[i].User == credentials[j].User {
+				return credentials[i].Mechanism < credentials[j].Mechanism
+			}
+			return credentials[i].User < credentials[j].User
+		})
+		resultChan <- credentials
+	}()
+}
+
+// UpsertScramCredential creates or replaces the credential of a user for a mechanism.
+func (client *Client) UpsertScramCredential(
+	credential ScramCredential,
+	password []byte,
+	resultChan chan<- bool,
+	errorChan chan<- error,
+) {
+	resultChan, errorChan = audited(client.ClusterName, "scram_credential", "upsert",
+		scramParams(credential), resultChan, errorChan)
+
+	go func() {
+		upsertion := kafka.UserScramCredentialUpsertion{
+			User:                credential.User,
+			ScramCredentialInfo: credential.ScramCredentialInfo,
+			Password:            password,
+		}
+		if upsertion.ScramCredentialInfo.Iterations == 0 {
+			upsertion.ScramCredentialInfo.Iterations = DefaultScramIterations
+		}
+
+		err := client.alterScramCredentials(
+			credential.User,
+			[]kafka.UserScramCredentialUpsertion{upsertion},
+			nil,
+		)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		resultChan <- true
+	}()
+}
+
+// DeleteScramCredential deletes the credential of a user for a mechanism.
+func (client *Client) DeleteScramCredential(
+	credential ScramCredential,
+	resultChan chan<- bool,
+	errorChan chan<- error,
+) {
+	resultChan, errorChan = audited(client.ClusterName, "scram_credential", "delete",
+		scramParams(credential), resultChan, errorChan)
+
+	go func() {
+		err := client.alterScramCredentials(
+			credential.User,
+			nil,
+			[]kafka.UserScramCredentialDeletion{{
+				User:      credential.User,
+				Mechanism: credential.Mechanism,
+			}},
+		)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		resultChan <- true
+	}()
+}
+
+func (client *Client) alterScramCredentials(
+	user string,
+	upsertions []kafka.UserScramCredentialUpsertion,
+	deletions []kafka.UserScramCredentialDeletion,
+) error {
+	ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+	defer cancel()
+
+	result, err := client.AlterUserScramCredentials(
+		ctx,
+		upsertions,
+		deletions,
+		kafka.SetAdminRequestTimeout(client.Timeout),
+	)
+	if err != nil {
+		return err
+	}
+	if e, ok := result.Errors[user]; ok && e.Code() != kafka.ErrNoError {
+		return fmt.Errorf("failed to alter credentials of user '%s': %s", user, e.String())
+	}
+	return nil
+}
@@ -0,0 +1,353 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"gopkg.in/yaml.v3"
+)
+
+// SensitiveValue replaces the value of sensitive config entries in a snapshot.
+const SensitiveValue = "(sensitive)"
+
+// Snapshot is the state of a cluster at a point in time.
+type Snapshot struct {
+	Cluster        string                  `yaml:"cluster"                  json:"cluster"`
+	ClusterID      string                  `yaml:"cluster_id"               json:"cluster_id"`
+	Controller     int                     `yaml:"controller"               json:"controller"`
+	TakenAt        time.Time               `yaml:"taken_at"                 json:"taken_at"`
+	Brokers        []BrokerSnapshot        `yaml:"brokers"                  json:"brokers"`
+	ClusterConfig  map[string]string       `yaml:"cluster_config,omitempty" json:"cluster_config,omitempty"`
+	Topics         []TopicSnapshot         `yaml:"topics"                   json:"topics"`
+	ConsumerGroups []ConsumerGroupSnapshot `yaml:"consumer_groups"          json:"consumer_groups"`
+}
+
+// BrokerSnapshot is a broker with its dynamic configs.
+type BrokerSnapshot struct {
+	ID     int               `yaml:"id"               json:"id"`
+	Host   string            `yaml:"host"             json:"host"`
+	Port   int               `yaml:"port"             json:"port"`
+	Rack   string            `yaml:"rack,omitempty"   json:"rack,omitempty"`
+	Config map[string]string `yaml:"config,omitempty" json:"config,omitempty"`
+}
+
+// TopicSnapshot is a topic with its partitions and the configs overridden on the topic.
+type TopicSnapshot struct {
+	Name              string              `yaml:"name"               json:"name"`
+	Internal          bool                `yaml:"internal,omitempty" json:"internal,omitempty"`
+	ReplicationFactor int                 `yaml:"replication_factor" json:"replication_factor"`
+	Partitions        []PartitionSnapshot `yaml:"partitions"         json:"partitions"`
+	Config            map[string]string   `yaml:"config,omitempty"   json:"config,omitempty"`
+}
+
+// PartitionSnapshot is the assignment of a partition.
+type PartitionSnapshot struct {
+	ID       int   `yaml:"id"       json:"id"`
+	Leader   int   `yaml:"leader"   json:"leader"`
+	Replicas []int `yaml:"replicas" json:"replicas"`
+	ISR      []int `yaml:"isr"      json:"isr"`
+}
+
+// ConsumerGroupSnapshot is a consumer group with its committed offsets.
+type ConsumerGroupSnapshot struct {
+	Group   string            `yaml:"group"             json:"group"`
+	State   string            `yaml:"state"             json:"state"`
+	Members int               `yaml:"members"           json:"members"`
+	Offsets []CommittedOffset `yaml:"offsets,omitempty" json:"offsets,omitempty"`
+	Error   string            `yaml:"error,omitempty"   json:"error,omitempty"`
+}
+
+// CommittedOffset is the committed offset of a group on a partition.
+type CommittedOffset struct {
+	Topic     string `yaml:"topic"     json:"topic"`
+	Partition int32  `yaml:"partition" json:"partition"`
+	Offset    int64  `yaml:"offset"    json:"offset"`
+}
+
+// SnapshotFileName is the default file name of a snapshot of a cluster taken at a time.
+func SnapshotFileName(cluster string, at time.Time) string {
+	return fmt.Sprintf("%s-snapshot-%s.yaml", cluster, at.Format("20060102-150405"))
+}
+
+// Write writes the snapshot to a file, as JSON when the file has the .json
+// extension and as YAML otherwise.
+func (s *Snapshot) Write(path string) error {
+	var data []byte
+	var err error
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err = json.MarshalIndent(s, "", "  ")
+	} else {
+		data, err = yaml.Marshal(s)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Snapshot collects the cluster description, every topic with its partitions and
+// overridden configs, the committed offsets of every consumer group, and the dynamic
+// configs of every broker and of the cluster. Each step has its own timeout.
+func (client *Client) Snapshot(resultChan chan<- *Snapshot, errorChan chan<- error) {
+	go func() {
+		snapshot := &Snapshot{Cluster: client.ClusterName, TakenAt: time.Now().UTC()}
+
+		steps := []func(context.Context, *Snapshot) error{
+			client.snapshotCluster,
+			client.snapshotTopics,
+			client.snapshotConsumerGroups,
+		}
+		for _, step := range steps {
+			ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+			err := step(ctx, snapshot)
+			cancel()
+			if err != nil {
+				errorChan <- err
+				return
+			}
+		}
+
+		resultChan <- snapshot
+	}()
+}
+
+func (client *Client) snapshotCluster(ctx context.Context, s *Snapshot) error {
+	desc, err := client.AdminClient.DescribeCluster(ctx, kafka.SetAdminRequestTimeout(client.Timeout))
+	if err != nil {
+		return fmt.Errorf("failed to describe cluster: %w", err)
+	}
+
+	if desc.ClusterID != nil {
+		s.ClusterID = *desc.ClusterID
+	}
+	s.Controller = NoLeader
+	if desc.Controller != nil {
+		s.Controller = desc.Controller.ID
+	}
+
+	resources := []kafka.ConfigResource{{Type: kafka.ResourceBroker, Name: ClusterDefaultBroker}}
+	for _, node := range desc.Nodes {
+		b := BrokerSnapshot{ID: node.ID, Host: node.Host, Port: node.Port}
+		if node.Rack != nil {
+			b.Rack = *node.Rack
+		}
+		s.Brokers = append(s.Brokers, b)
+		resources = append(resources, kafka.ConfigResource{
+			Type: kafka.ResourceBroker,
+			Name: strconv.Itoa(node.ID),
+		})
+	}
+	sort.Slice(s.Brokers, func(i, j int) bool { return s.Brokers[i].ID < s.Brokers[j].ID })
+
+	// Brokers only describe their own configs, so they are requested one by one.
+	for _, r := range resources {
+		results, err := client.DescribeConfigs(
+			ctx,
+			[]kafka.ConfigResource{r},
+			kafka.SetAdminRequestTimeout(client.Timeout),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to describe configs of broker '%s': %w", r.Name, err)
+		}
+		for _, result := range results {
+			if result.Error.Code() != kafka.ErrNoError {
+				return fmt.Errorf(
+					"failed to describe configs of broker '%s': %s",
+					r.Name,
+					result.Error.String(),
+				)
+			}
+			if r.Name == ClusterDefaultBroker {
+				s.ClusterConfig = configOf(result, kafka.ConfigSourceDynamicDefaultBroker)
+				continue
+			}
+			id, _ := strconv.Atoi(r.Name)
+			for i := range s.Brokers {
+				if s.Brokers[i].ID == id {
+					s.Brokers[i].Config = configOf(result, kafka.ConfigSourceDynamicBroker)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (client *Client) snapshotTopics(ctx context.Context, s *Snapshot) error {
+	metadata, err := client.GetMetadata(nil, true, int(client.Timeout.Milliseconds()))
+	if err != nil {
+		return fmt.Errorf("failed to list topics: %w", err)
+	}
+
+	names := make([]string, 0, len(metadata.Topics))
+	for name := range metadata.Topics {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+
+	desc, err := client.DescribeTopics(
+		ctx,
+		kafka.NewTopicCollectionOfTopicNames(names),
+		kafka.SetAdminRequestTimeout(client.Timeout),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to describe topics: %w", err)
+	}
+
+	resources := make([]kafka.ConfigResource, len(names))
+	for i, name := range names {
+		resources[i] = kafka.ConfigResource{Type: kafka.ResourceTopic, Name: name}
+	}
+	configs, err := client.DescribeConfigs(ctx, resources, kafka.SetAdminRequestTimeout(client.Timeout))
+	if err != nil {
+		return fmt.Errorf("failed to describe topic configs: %w", err)
+	}
+	overrides := make(map[string]map[string]string, len(configs))
+	for _, r := range configs {
+		if r.Error.Code() != kafka.ErrNoError {
+			return fmt.Errorf("failed to describe config of topic '%s': %s", r.Name, r.Error.String())
+		}
+		overrides[r.Name] = configOf(r, kafka.ConfigSourceDynamicTopic)
+	}
+
+	for _, d := range desc.TopicDescriptions {
+		if d.Error.Code() != kafka.ErrNoError {
+			return fmt.Errorf("failed to describe topic '%s': %s", d.Name, d.Error.String())
+		}
+		t := TopicSnapshot{Name: d.Name, Internal: d.IsInternal, Config: overrides[d.Name]}
+		for _, p := range d.Partitions {
+			ps := PartitionSnapshot{ID: p.Partition, Leader: NoLeader}
+			if p.Leader != nil && p.Leader.ID >= 0 {
+				ps.Leader = p.Leader.ID
+			}
+			for _, r := range p.Replicas {
+				ps.Replicas = append(ps.Replicas, r.ID)
+			}
+			for _, r := range p.Isr {
+				ps.ISR = append(ps.ISR, r.ID)
+			}
+			t.Partitions = append(t.Partitions, ps)
+		}
+		sort.Slice(t.Partitions, func(i, j int) bool { return t.Partitions[i].ID < t.Partitions[j].ID })
+		if len(t.Partitions) > 0 {
+			t.ReplicationFactor = len(t.Partitions[0].Replicas)
+		}
+		s.Topics = append(s.Topics, t)
+	}
+	sort.Slice(s.Topics, func(i, j int) bool { return s.Topics[i].Name < s.Topics[j].Name })
+	return nil
+}
+
+func (client *Client) snapshotConsumerGroups(ctx context.Context, s *Snapshot) error {
+	listed, err := client.ListConsumerGroups(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list consumer groups: %w", err)
+	}
+	if len(listed.Valid) == 0 {
+		return nil
+	}
+
+	groups := make([]ConsumerGroupSnapshot, len(listed.Valid))
+	ids := make([]string, len(listed.Valid))
+	index := make(map[string]int, len(listed.Valid))
+	for i, g := range listed.Valid {
+		groups[i] = ConsumerGroupSnapshot{Group: g.GroupID, State: g.State.String()}
+		ids[i] = g.GroupID
+		index[g.GroupID] = i
+	}
+
+	described, err := client.DescribeConsumerGroups(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to describe consumer groups: %w", err)
+	}
+	for _, d := range described.ConsumerGroupDescriptions {
+		if i, ok := index[d.GroupID]; ok && d.Error.Code() == kafka.ErrNoError {
+			groups[i].State = d.State.String()
+			groups[i].Members = len(d.Members)
+		}
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(LagOverviewWorkers, len(groups)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				client.committedOffsets(ctx, &groups[i])
+			}
+		}()
+	}
+	for i := range groups {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Group < groups[j].Group })
+	s.ConsumerGroups = groups
+	return nil
+}
+
+// committedOffsets fills the committed offsets of a group, partitions without a
+// committed offset are left out.
+func (client *Client) committedOffsets(ctx context.Context, group *ConsumerGroupSnapshot) {
+	errs := make(chan error, 1)
+	result := &DescribeConsumerGroupResult{}
+
+	client.CurrentOffsets(ctx, group.Group, errs, result)
+	select {
+	case err := <-errs:
+		group.Error = err.Error()
+		return
+	default:
+	}
+
+	for tp, offset := range result.currentOffsets {
+		if offset >= 0 {
+			group.Offsets = append(group.Offsets, CommittedOffset{tp.Topic, tp.Partition, int64(offset)})
+		}
+	}
+	sort.Slice(group.Offsets, func(i, j int) bool {
+		a, b := group.Offsets[i], group.Offsets[j]
+		if a.Topic == b.Topic {
+			return a.Partition < b.Partition
+		}
+		return a.Topic < b.Topic
+	})
+}
+
+// configOf returns the config entries of a resource set from the given source,
+// with sensitive values masked.
+func configOf(result kafka.ConfigResourceResult, source kafka.ConfigSource) map[string]string {
+	config := make(map[string]string)
+	for name, entry := range result.Config {
+		if entry.Source != source {
+			continue
+		}
+		if entry.IsSensitive {
+			config[name] = SensitiveValue
+		} else {
+			config[name] = entry.Value
+		}
+	}
+	if len(config) == 0 {
+		return nil
+	}
+	return config
+}
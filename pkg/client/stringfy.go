@@ -5,9 +5,13 @@
 package client
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"text/tabwriter"
+	"time"
+	"unicode/utf8"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"github.com/emirpasic/gods/maps/treemap"
@@ -21,6 +25,9 @@ func (r *ClusterResult) String() string {
 	output += fmt.Sprintf("ClusterId: %s\n", *r.ClusterID)
 	output += fmt.Sprintf("Controller: %s\n", *r.Controller)
 	output += fmt.Sprintf("Allowed operations: %s\n", r.AuthorizedOperations)
+	if r.Token != nil {
+		output += r.Token.String()
+	}
 	var sb strings.Builder
 	func(res kafka.DescribeClusterResult) {
 		sb.WriteString("Nodes:\n")
@@ -32,6 +39,28 @@ func (r *ClusterResult) String() string {
 	return output
 }
 
+func (t *Token) String() string {
+	var output string
+	if !t.Expiration.IsZero() {
+		output += fmt.Sprintf("Token principal: %s\n", t.Principal)
+		expires := t.Expiration.Local().Format(time.DateTime)
+		if remaining := time.Until(t.Expiration); remaining > 0 {
+			expires += fmt.Sprintf(" (in %s)", remaining.Round(time.Second))
+		} else {
+			expires += " (expired)"
+		}
+		output += fmt.Sprintf("Token expires: %s\n", expires)
+	}
+	if t.Err != nil {
+		output += fmt.Sprintf(
+			"Token refresh failed at %s: %s\n",
+			t.Refreshed.Local().Format(time.DateTime),
+			t.Err,
+		)
+	}
+	return output
+}
+
 func (r *ResourceResult) String() string {
 	var sb strings.Builder
 	for _, result := range r.Results {
@@ -81,6 +110,8 @@ func (r *TopicResult) String() string {
 			st := r.startOffsets[int32(p.Partition)]
 			sb.WriteString(fmt.Sprintf("\t%d: [%d, %d] %d\n", p.Partition, st, end, end-st))
 		}
+		sb.WriteString("Throughput: \n")
+		r.writeThroughput(&sb, desc.Partitions)
 		sb.WriteString("Partitions details: \n")
 		for _, p := range desc.Partitions {
 			sb.WriteString(fmt.Sprintf("\tPartition: %d\n", p.Partition))
@@ -93,9 +124,38 @@ func (r *TopicResult) String() string {
 		sb.WriteString("\n")
 	}
 
-	//for _, acl := range r.DescribeACLsResult.ACLBindings {
-	//	sb.WriteString(fmt.Sprintf("ACL Bindings: %s\n", acl))
-	//}
+	sb.WriteString("ACL bindings: \n")
+	if r.DescribeACLsResult.Error.Code() != kafka.ErrNoError {
+		sb.WriteString(fmt.Sprintf("\tunavailable: %s\n", r.DescribeACLsResult.Error.String()))
+	} else if len(r.ACLBindings) == 0 {
+		sb.WriteString("\tnone\n")
+	} else {
+		w := tabwriter.NewWriter(&sb, 0, 0, 1, ' ', 0)
+		_, err := fmt.Fprintln(w, "\tPrincipal\tHost\tOperation\tPermission\tPattern\tResource")
+		if err != nil {
+			log.Error().Err(err).Msg("Error to write Topic ACL bindings")
+		}
+		for _, b := range r.ACLBindings {
+			_, err := fmt.Fprintf(
+				w,
+				"\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				b.Principal,
+				b.Host,
+				b.Operation,
+				b.PermissionType,
+				b.ResourcePatternType,
+				b.Name,
+			)
+			if err != nil {
+				log.Error().Err(err).Msg("Error to write Topic ACL bindings")
+			}
+		}
+		err = w.Flush()
+		if err != nil {
+			log.Error().Err(err).Msg("Error to flush Topic ACL bindings")
+		}
+	}
+	sb.WriteString("\n")
 
 	for _, result := range r.Config {
 		w := tabwriter.NewWriter(&sb, 0, 0, 1, ' ', 0)
@@ -133,6 +193,32 @@ func (r *TopicResult) String() string {
 	return sb.String()
 }
 
+// writeThroughput writes the sampled produce rates per partition and of the whole
+// topic. Hot partitions are highlighted and idle partitions dimmed.
+func (r *TopicResult) writeThroughput(sb *strings.Builder, partitions []kafka.TopicPartitionInfo) {
+	r.mx.RLock()
+	t := r.throughput
+	r.mx.RUnlock()
+
+	if t == nil {
+		sb.WriteString("\tsampling...\n")
+		return
+	}
+
+	for _, p := range partitions {
+		id := int32(p.Partition)
+		rate := fmt.Sprintf("%.1f msg/s", t.Rates[id])
+		switch {
+		case t.Hot(id):
+			rate = "[red]" + rate + " hot[-]"
+		case t.Idle(id):
+			rate = "[gray]" + rate + " idle[-]"
+		}
+		sb.WriteString(fmt.Sprintf("\t%d: %s\n", p.Partition, rate))
+	}
+	sb.WriteString(fmt.Sprintf("\tTotal: %.1f msg/s over %s\n", t.Total(), t.Interval.Round(time.Second)))
+}
+
 func (r *DescribeConsumerGroupResult) String() string {
 	var sb strings.Builder
 	members := make(map[TopicPartition]kafka.MemberDescription)
@@ -209,3 +295,148 @@ func (r *DescribeConsumerGroupResult) String() string {
 
 	return sb.String()
 }
+
+func (r *Record) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Topic: %s\n", r.Topic))
+	sb.WriteString(fmt.Sprintf("Partition: %d\n", r.Partition))
+	sb.WriteString(fmt.Sprintf("Offset: %d\n", r.Offset))
+	sb.WriteString(fmt.Sprintf("Timestamp: %s\n", r.Timestamp.Format(time.RFC3339Nano)))
+	sb.WriteString(fmt.Sprintf("Key: %s\n", r.KeyString()))
+	sb.WriteString("Headers:\n")
+	for _, h := range r.Headers {
+		sb.WriteString(fmt.Sprintf("\t%s: %s\n", h.Key, FormatBytes(h.Value)))
+	}
+	sb.WriteString("Value:\n")
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, []byte(r.ValueString()), "", "  "); err == nil {
+		sb.WriteString(pretty.String())
+	} else {
+		sb.WriteString(r.ValueString())
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// KeyString returns the decoded key if available, the raw key otherwise.
+func (r *Record) KeyString() string {
+	if r.DecodedKey != "" {
+		return r.DecodedKey
+	}
+	return FormatBytes(r.Key)
+}
+
+// ValueString returns the decoded value if available, the raw value otherwise.
+func (r *Record) ValueString() string {
+	if r.DecodedValue != "" {
+		return r.DecodedValue
+	}
+	return FormatBytes(r.Value)
+}
+
+// FormatBytes renders a key, value or header as text, falling back to hex
+// for binary content.
+func FormatBytes(b []byte) string {
+	if b == nil {
+		return "<null>"
+	}
+	if utf8.Valid(b) {
+		return string(b)
+	}
+	return fmt.Sprintf("0x%x", b)
+}
+
+func (r *ProduceResult) String() string {
+	var failed []DeliveryReport
+	for _, report := range r.Reports {
+		if report.Error != nil {
+			failed = append(failed, report)
+		}
+	}
+
+	if r.EnqueueError != nil {
+		return fmt.Sprintf(
+			"%d of %d records sent to '%s', %d delivered: %s",
+			len(r.Reports),
+			r.Count,
+			r.Topic,
+			len(r.Reports)-len(failed),
+			r.EnqueueError.Error(),
+		)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Sprintf(
+			"%d of %d records failed to be delivered to '%s': %s",
+			len(failed),
+			len(r.Reports),
+			r.Topic,
+			failed[0].Error.Error(),
+		)
+	}
+
+	if len(r.Reports) == 1 {
+		report := r.Reports[0]
+		return fmt.Sprintf(
+			"record delivered to '%s' partition %d at offset %d",
+			r.Topic,
+			report.Partition,
+			report.Offset,
+		)
+	}
+
+	partitions := treemap.NewWith(utils.Int32Comparator)
+	for _, report := range r.Reports {
+		offsets, found := partitions.Get(report.Partition)
+		if !found {
+			partitions.Put(report.Partition, [2]kafka.Offset{report.Offset, report.Offset})
+			continue
+		}
+		o := offsets.([2]kafka.Offset)
+		partitions.Put(report.Partition, [2]kafka.Offset{min(o[0], report.Offset), max(o[1], report.Offset)})
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d records delivered to '%s':", len(r.Reports), r.Topic))
+	partitions.Each(func(key, value any) {
+		o := value.([2]kafka.Offset)
+		sb.WriteString(fmt.Sprintf(" p%d:%d..%d", key.(int32), o[0], o[1]))
+	})
+	return sb.String()
+}
+
+// HasErrors reports whether any record failed to be sent or delivered.
+func (r *ProduceResult) HasErrors() bool {
+	if r.EnqueueError != nil {
+		return true
+	}
+	for _, report := range r.Reports {
+		if report.Error != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *DeleteConsumerGroupsResult) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d consumer groups deleted", len(r.Deleted)))
+
+	failed := treemap.NewWithStringComparator()
+	for group, err := range r.Errors {
+		failed.Put(group, err)
+	}
+	failed.Each(func(group, err interface{}) {
+		sb.WriteString(fmt.Sprintf("; '%s': %s", group, err.(error).Error()))
+	})
+	return sb.String()
+}
+
+func (r *DeleteRecordsResult) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("records of '%s' deleted, low watermarks:", r.Topic))
+	for _, p := range r.Partitions {
+		sb.WriteString(fmt.Sprintf(" p%d:%d", p, r.Low[p]))
+	}
+	return sb.String()
+}
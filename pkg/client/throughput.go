@@ -0,0 +1,102 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+// HotPartitionFactor is how many times the mean rate of the topic a partition
+// has to receive to be considered hot.
+const HotPartitionFactor = 2
+
+// OffsetsSample contains the end offsets of the partitions of a topic at a point in time.
+type OffsetsSample struct {
+	At      time.Time
+	Offsets map[int32]kafka.Offset
+}
+
+// Throughput contains the produce rate in messages per second of the partitions
+// of a topic between two samples of their end offsets.
+type Throughput struct {
+	Interval time.Duration
+	Rates    map[int32]float64
+}
+
+// NewThroughput derives the produce rates from two samples of end offsets.
+func NewThroughput(from, to *OffsetsSample) *Throughput {
+	t := &Throughput{
+		Interval: to.At.Sub(from.At),
+		Rates:    make(map[int32]float64, len(to.Offsets)),
+	}
+	seconds := t.Interval.Seconds()
+	for p, end := range to.Offsets {
+		if start, ok := from.Offsets[p]; ok && seconds > 0 {
+			t.Rates[p] = float64(end-start) / seconds
+		}
+	}
+	return t
+}
+
+// Total returns the produce rate of the whole topic.
+func (t *Throughput) Total() float64 {
+	total := 0.0
+	for _, r := range t.Rates {
+		total += r
+	}
+	return total
+}
+
+// Idle reports whether no messages were produced to the partition.
+func (t *Throughput) Idle(partition int32) bool {
+	return t.Rates[partition] <= 0
+}
+
+// Hot reports whether the partition receives at least HotPartitionFactor times
+// the mean rate of the partitions of the topic.
+func (t *Throughput) Hot(partition int32) bool {
+	if len(t.Rates) < 2 {
+		return false
+	}
+	mean := t.Total() / float64(len(t.Rates))
+	return mean > 0 && t.Rates[partition] >= HotPartitionFactor*mean
+}
+
+// SampleEndOffsets lists the latest offsets of the given partitions of a topic.
+func (client *Client) SampleEndOffsets(
+	name string,
+	partitions []int32,
+	resultChan chan<- *OffsetsSample,
+	errorChan chan<- error,
+) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+		defer cancel()
+
+		request := make(map[kafka.TopicPartition]kafka.OffsetSpec, len(partitions))
+		for _, p := range partitions {
+			request[kafka.TopicPartition{Topic: &name, Partition: p}] = kafka.LatestOffsetSpec
+		}
+
+		result, err := client.ListOffsets(ctx, request)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+
+		sample := &OffsetsSample{At: time.Now(), Offsets: make(map[int32]kafka.Offset, len(partitions))}
+		for tp, info := range result.ResultInfos {
+			if info.Error.Code() != kafka.ErrNoError {
+				errorChan <- info.Error
+				return
+			}
+			sample.Offsets[tp.Partition] = info.Offset
+		}
+		resultChan <- sample
+	}()
+}
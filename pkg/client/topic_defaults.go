@@ -0,0 +1,139 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+// topicConfigSynonyms maps topic config entries to the broker configs providing
+// their default, in order of precedence. Entries missing here have the same name
+// on the broker, e.g. min.insync.replicas.
+var topicConfigSynonyms = map[string][]brokerSynonym{
+	"cleanup.policy":                      {{"log.cleanup.policy", 1}},
+	"delete.retention.ms":                 {{"log.cleaner.delete.retention.ms", 1}},
+	"file.delete.delay.ms":                {{"log.segment.delete.delay.ms", 1}},
+	"flush.messages":                      {{"log.flush.interval.messages", 1}},
+	"flush.ms":                            {{"log.flush.interval.ms", 1}},
+	"index.interval.bytes":                {{"log.index.interval.bytes", 1}},
+	"local.retention.bytes":               {{"log.local.retention.bytes", 1}},
+	"local.retention.ms":                  {{"log.local.retention.ms", 1}},
+	"max.compaction.lag.ms":               {{"log.cleaner.max.compaction.lag.ms", 1}},
+	"max.message.bytes":                   {{"message.max.bytes", 1}},
+	"message.downconversion.enable":       {{"log.message.downconversion.enable", 1}},
+	"message.format.version":              {{"log.message.format.version", 1}},
+	"message.timestamp.difference.max.ms": {{"log.message.timestamp.difference.max.ms", 1}},
+	"message.timestamp.type":              {{"log.message.timestamp.type", 1}},
+	"min.cleanable.dirty.ratio":           {{"log.cleaner.min.cleanable.ratio", 1}},
+	"min.compaction.lag.ms":               {{"log.cleaner.min.compaction.lag.ms", 1}},
+	"preallocate":                         {{"log.preallocate", 1}},
+	"retention.bytes":                     {{"log.retention.bytes", 1}},
+	"retention.ms": {
+		{"log.retention.ms", 1},
+		{"log.retention.minutes", 60 * 1000},
+		{"log.retention.hours", 60 * 60 * 1000},
+	},
+	"segment.bytes":       {{"log.segment.bytes", 1}},
+	"segment.index.bytes": {{"log.index.size.max.bytes", 1}},
+	"segment.jitter.ms": {
+		{"log.roll.jitter.ms", 1},
+		{"log.roll.jitter.hours", 60 * 60 * 1000},
+	},
+	"segment.ms": {
+		{"log.roll.ms", 1},
+		{"log.roll.hours", 60 * 60 * 1000},
+	},
+}
+
+// brokerSynonym is a broker config providing the default of a topic config entry.
+// Values in coarser units, e.g. hours, are multiplied by scale.
+type brokerSynonym struct {
+	name  string
+	scale int64
+}
+
+// TopicDefaults returns the values a new topic gets for the given config entries
+// when they are not set, as configured on a broker of the cluster. Entries without
+// a broker default are missing from the result. No request is made without keys.
+func (client *Client) TopicDefaults(keys []string) (map[string]string, error) {
+	defaults := make(map[string]string)
+	if len(keys) == 0 {
+		return defaults, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), client.Timeout)
+	defer cancel()
+
+	desc, err := client.AdminClient.DescribeCluster(ctx, kafka.SetAdminRequestTimeout(client.Timeout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe cluster: %w", err)
+	}
+	if len(desc.Nodes) == 0 {
+		return nil, fmt.Errorf("no brokers to describe topic defaults")
+	}
+
+	// Brokers only describe their own configs, one of them is enough for defaults.
+	broker := strconv.Itoa(desc.Nodes[0].ID)
+	results, err := client.DescribeConfigs(
+		ctx,
+		[]kafka.ConfigResource{{Type: kafka.ResourceBroker, Name: broker}},
+		kafka.SetAdminRequestTimeout(client.Timeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe configs of broker '%s': %w", broker, err)
+	}
+
+	config := make(map[string]string)
+	for _, result := range results {
+		if result.Error.Code() != kafka.ErrNoError {
+			return nil, fmt.Errorf(
+				"failed to describe configs of broker '%s': %s",
+				broker,
+				result.Error.String(),
+			)
+		}
+		for name, entry := range result.Config {
+			if !entry.IsSensitive {
+				config[name] = entry.Value
+			}
+		}
+	}
+
+	for _, key := range keys {
+		if value, ok := topicDefault(key, config); ok {
+			defaults[key] = value
+		}
+	}
+	return defaults, nil
+}
+
+// topicDefault resolves the default of a topic config entry from the configs of
+// a broker. Unset broker configs are empty and fall through to the next synonym.
+func topicDefault(key string, brokerConfig map[string]string) (string, bool) {
+	synonyms, ok := topicConfigSynonyms[key]
+	if !ok {
+		synonyms = []brokerSynonym{{key, 1}}
+	}
+
+	for _, s := range synonyms {
+		value := brokerConfig[s.name]
+		if value == "" {
+			continue
+		}
+		if s.scale == 1 {
+			return value, true
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		return strconv.FormatInt(n*s.scale, 10), true
+	}
+	return "", false
+}
@@ -0,0 +1,39 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package client
+
+import "testing"
+
+func TestTopicDefault(t *testing.T) {
+	broker := map[string]string{
+		"min.insync.replicas": "2",
+		"log.cleanup.policy":  "delete",
+		"log.retention.ms":    "",
+		"log.retention.hours": "168",
+		"log.roll.ms":         "3600000",
+		"log.roll.hours":      "168",
+	}
+
+	tests := []struct {
+		key   string
+		want  string
+		found bool
+	}{
+		{key: "min.insync.replicas", want: "2", found: true},
+		{key: "cleanup.policy", want: "delete", found: true},
+		{key: "retention.ms", want: "604800000", found: true},
+		{key: "segment.ms", want: "3600000", found: true},
+		{key: "retention.bytes", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, found := topicDefault(tt.key, broker)
+			if got != tt.want || found != tt.found {
+				t.Errorf("topicDefault(%q) = %q, %t, want %q, %t", tt.key, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
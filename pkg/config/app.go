@@ -6,6 +6,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"time"
 
@@ -24,6 +25,8 @@ type Config struct {
 		SchemaRegistries []*SchemaRegistryConfig `yaml:"schema-registries"`
 		CliTemplates     []string                `yaml:"cli_templates,omitempty"`
 		API              ApiConfig               `yaml:"api,omitempty"`
+		Peek             PeekConfig              `yaml:"peek,omitempty"`
+		Monitor          MonitorConfig           `yaml:"monitor,omitempty"`
 	} `yaml:"cinnamon"`
 }
 
@@ -31,17 +34,59 @@ type ApiConfig struct {
 	Timeout int `yaml:"timeout"`
 }
 
+type PeekConfig struct {
+	Records int `yaml:"records"`
+}
+
+// MonitorConfig configures pages that sample the cluster periodically.
+type MonitorConfig struct {
+	// Interval between samples in seconds.
+	Interval int `yaml:"interval"`
+	// History is the number of samples kept per series.
+	History int `yaml:"history"`
+}
+
 type ClusterConfig struct {
 	Name       string            `yaml:"name"`
 	Properties map[string]string `yaml:"properties"`
-	Selected   bool              `yaml:"selected,omitempty"`
+	// Policies are rules every topic of the cluster must satisfy, e.g.
+	// "replication.factor >= 3" or "name =~ ^[a-z0-9.-]+$".
+	Policies []string `yaml:"policies,omitempty"`
+	// ReadOnly disables every action that changes the cluster.
+	ReadOnly bool `yaml:"readOnly,omitempty"`
+	// Protected requires typing the resource name to confirm destructive actions.
+	Protected bool `yaml:"protected,omitempty"`
+	// OAuth obtains the tokens of a cluster using sasl.mechanism OAUTHBEARER.
+	OAuth    *OAuthConfig `yaml:"oauth,omitempty"`
+	Selected bool         `yaml:"selected,omitempty"`
+}
+
+// OAuthConfig configures how SASL/OAUTHBEARER tokens are obtained: from an OIDC
+// token endpoint with the client credentials grant or from a command printing
+// either a token or a token endpoint response.
+type OAuthConfig struct {
+	TokenEndpoint string `yaml:"tokenEndpoint,omitempty"`
+	ClientID      string `yaml:"clientId,omitempty"`
+	ClientSecret  string `yaml:"clientSecret,omitempty"`
+	Scope         string `yaml:"scope,omitempty"`
+	Command       string `yaml:"command,omitempty"`
+	// Principal defaults to the subject of the token, then to the client ID.
+	Principal  string            `yaml:"principal,omitempty"`
+	Extensions map[string]string `yaml:"extensions,omitempty"`
 }
 
-func (c *ClusterConfig) GetBootstrapServers() string {
-	if bootstrap, ok := c.Properties["bootstrap.servers"]; ok {
-		return bootstrap
+// GetBootstrapServers returns the bootstrap servers of the cluster with their
+// secret references resolved.
+func (c *ClusterConfig) GetBootstrapServers() (string, error) {
+	bootstrap, ok := c.Properties["bootstrap.servers"]
+	if !ok {
+		return "", nil
+	}
+	resolved, err := Resolve(bootstrap)
+	if err != nil {
+		return "", fmt.Errorf("property 'bootstrap.servers' of cluster '%s': %w", c.Name, err)
 	}
-	return ""
+	return resolved, nil
 }
 
 // GetAPICallTimeout returns the API call timeout duration.
@@ -53,6 +98,33 @@ func (c *Config) GetAPICallTimeout() time.Duration {
 	return time.Duration(c.Cinnamon.API.Timeout) * time.Second
 }
 
+// GetPeekRecords returns the number of latest records read per partition on peek.
+// Returns 10 as default if not configured or invalid.
+func (c *Config) GetPeekRecords() int {
+	if c.Cinnamon.Peek.Records <= 0 {
+		return 10
+	}
+	return c.Cinnamon.Peek.Records
+}
+
+// GetMonitorInterval returns the interval between samples of monitoring pages.
+// Returns 5 seconds as default if not configured or invalid.
+func (c *Config) GetMonitorInterval() time.Duration {
+	if c.Cinnamon.Monitor.Interval <= 0 {
+		return 5 * time.Second
+	}
+	return time.Duration(c.Cinnamon.Monitor.Interval) * time.Second
+}
+
+// GetMonitorHistory returns the number of samples kept by monitoring pages.
+// Returns 60 as default if not configured or invalid.
+func (c *Config) GetMonitorHistory() int {
+	if c.Cinnamon.Monitor.History < 2 {
+		return 60
+	}
+	return c.Cinnamon.Monitor.History
+}
+
 // SchemaRegistryConfig holds Schema Registry connection properties.
 type SchemaRegistryConfig struct {
 	Name                   string `yaml:"name"`
@@ -71,15 +143,14 @@ func LoadAppConfig() (*Config, error) {
 
 	data, err := os.ReadFile(configPath)
 	if err != nil {
-		log.Fatal().Err(err).Msg("error reading config file")
-		return nil, err
+		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
-	content := os.ExpandEnv(string(data))
 
+	// Secret references such as ${env:VAR} are kept as they are and resolved only
+	// when connecting, so that saving the config never writes a resolved secret.
 	config := &Config{}
-	if err := yaml.Unmarshal([]byte(content), config); err != nil {
-		log.Fatal().Err(err).Msg("error unmarshalling config")
-		return nil, err
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file '%s': %w", configPath, err)
 	}
 
 	return config, nil
@@ -5,10 +5,9 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
-
-	"github.com/rs/zerolog/log"
 )
 
 const (
@@ -29,8 +28,7 @@ func GetConfigPath() (string, error) {
 	default:
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
-			log.Fatal().Err(err).Msg("error getting home directory")
-			return "", err
+			return "", fmt.Errorf("failed to get home directory: %w", err)
 		}
 		configDir = homeDir
 	}
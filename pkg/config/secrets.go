@@ -0,0 +1,225 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SecretCommandTimeout bounds the time a ${cmd:...} reference or a token command
+// may take.
+const SecretCommandTimeout = 30 * time.Second
+
+// Kinds of secret references.
+const (
+	secretCmd  = "cmd"
+	secretFile = "file"
+	secretEnv  = "env"
+)
+
+// secretRegex matches ${cmd:...}, ${file:...}, ${env:...} and ${VAR}, which is
+// the same as ${env:VAR}.
+var secretRegex = regexp.MustCompile(`\$\{(?:(cmd|file|env):)?([^}]*)\}`)
+
+var (
+	secretsMx sync.Mutex
+	// secrets caches the output of commands and the content of files by reference,
+	// so that a command runs once per process.
+	secrets = make(map[string]string)
+	// inflight holds the references being resolved, callers asking for one of them
+	// wait for its outcome instead of running the command again.
+	inflight = make(map[string]*secretCall)
+)
+
+// secretCall is a command run or file read shared by the callers of a reference.
+type secretCall struct {
+	done   chan struct{}
+	secret string
+	err    error
+}
+
+// Resolve replaces the secret references of a value:
+//   - ${cmd:<command>} with the output of a shell command, e.g. ${cmd:pass show kafka/prod}
+//   - ${file:<path>} with the content of a file, e.g. ${file:/run/secrets/kafka}
+//   - ${env:<name>} and ${<name>} with the value of an environment variable
+//
+// A single trailing newline of outputs and files is removed. Commands and files
+// are cached, environment variables are read every time.
+func Resolve(value string) (string, error) {
+	var errs []error
+	resolved := secretRegex.ReplaceAllStringFunc(value, func(ref string) string {
+		match := secretRegex.FindStringSubmatch(ref)
+		kind, arg := match[1], strings.TrimSpace(match[2])
+		if kind == "" {
+			kind = secretEnv
+		}
+
+		secret, err := resolveSecret(kind, arg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to resolve %s: %w", ref, err))
+			return ref
+		}
+		return secret
+	})
+	if len(errs) > 0 {
+		return "", errors.Join(errs...)
+	}
+	return resolved, nil
+}
+
+func resolveSecret(kind, arg string) (string, error) {
+	if arg == "" {
+		return "", errors.New("empty reference")
+	}
+	if kind == secretEnv {
+		value, ok := os.LookupEnv(arg)
+		if !ok {
+			return "", fmt.Errorf("environment variable '%s' is not set", arg)
+		}
+		return value, nil
+	}
+
+	key := kind + ":" + arg
+	secretsMx.Lock()
+	if secret, ok := secrets[key]; ok {
+		secretsMx.Unlock()
+		return secret, nil
+	}
+	if call, ok := inflight[key]; ok {
+		secretsMx.Unlock()
+		<-call.done
+		return call.secret, call.err
+	}
+	call := &secretCall{done: make(chan struct{})}
+	inflight[key] = call
+	secretsMx.Unlock()
+
+	// The lock is not held while the command runs, other references resolve meanwhile.
+	switch kind {
+	case secretCmd:
+		call.secret, call.err = RunCommand(arg)
+	case secretFile:
+		call.secret, call.err = readSecretFile(arg)
+	}
+
+	secretsMx.Lock()
+	delete(inflight, key)
+	if call.err == nil {
+		secrets[key] = call.secret
+	}
+	secretsMx.Unlock()
+	close(call.done)
+
+	return call.secret, call.err
+}
+
+// RunCommand runs a shell command and returns its output without the trailing
+// newline. Unlike ${cmd:...} references, the output is not cached.
+func RunCommand(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), SecretCommandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("command did not finish within %s", SecretCommandTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return trimNewline(stdout.String()), nil
+}
+
+func readSecretFile(path string) (string, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = home + "/" + rest
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return trimNewline(string(data)), nil
+}
+
+func trimNewline(s string) string {
+	s = strings.TrimSuffix(s, "\n")
+	return strings.TrimSuffix(s, "\r")
+}
+
+// ResolvedProperties returns the properties of the cluster with their secret
+// references resolved. The config itself keeps the references.
+func (c *ClusterConfig) ResolvedProperties() (map[string]string, error) {
+	properties := make(map[string]string, len(c.Properties))
+	for key, value := range c.Properties {
+		resolved, err := Resolve(value)
+		if err != nil {
+			return nil, fmt.Errorf("property '%s' of cluster '%s': %w", key, c.Name, err)
+		}
+		properties[key] = resolved
+	}
+	return properties, nil
+}
+
+// Resolved returns a copy of the schema registry config with the secret references
+// of its URL and credentials resolved.
+func (c *SchemaRegistryConfig) Resolved() (*SchemaRegistryConfig, error) {
+	resolved := *c
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"schema.registry.url", &resolved.SchemaRegistryURL},
+		{"schema.registry.sasl.username", &resolved.SchemaRegistryUsername},
+		{"schema.registry.sasl.password", &resolved.SchemaRegistryPassword},
+	}
+	for _, f := range fields {
+		value, err := Resolve(*f.value)
+		if err != nil {
+			return nil, fmt.Errorf("%s of schema registry '%s': %w", f.name, c.Name, err)
+		}
+		*f.value = value
+	}
+	return &resolved, nil
+}
+
+// Resolved returns a copy of the OAuth config with the secret references of its
+// token endpoint and client credentials resolved. The command is run as it is.
+func (c *OAuthConfig) Resolved(cluster string) (*OAuthConfig, error) {
+	resolved := *c
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"tokenEndpoint", &resolved.TokenEndpoint},
+		{"clientId", &resolved.ClientID},
+		{"clientSecret", &resolved.ClientSecret},
+	}
+	for _, f := range fields {
+		value, err := Resolve(*f.value)
+		if err != nil {
+			return nil, fmt.Errorf("oauth %s of cluster '%s': %w", f.name, cluster, err)
+		}
+		*f.value = value
+	}
+	return &resolved, nil
+}
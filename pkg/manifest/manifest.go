@@ -0,0 +1,66 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package manifest provides declarative topic manifests that are planned
+// against and applied to a live cluster.
+package manifest
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest declares the desired state of the topics of a cluster.
+type Manifest struct {
+	Topics []TopicSpec `yaml:"topics"`
+}
+
+// TopicSpec declares a single topic. Only the config entries listed are managed,
+// entries missing from the manifest are left untouched on the cluster.
+type TopicSpec struct {
+	Name              string            `yaml:"name"`
+	Partitions        int               `yaml:"partitions"`
+	ReplicationFactor int               `yaml:"replication_factor"`
+	Config            map[string]string `yaml:"config,omitempty"`
+}
+
+// Load reads and validates a manifest file.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{}
+	if err := yaml.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest '%s': %w", path, err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid manifest '%s': %w", path, err)
+	}
+	return m, nil
+}
+
+// Validate checks that every topic has a unique name, partitions and a replication factor.
+func (m *Manifest) Validate() error {
+	seen := make(map[string]bool, len(m.Topics))
+	for i, t := range m.Topics {
+		if t.Name == "" {
+			return fmt.Errorf("topic #%d has no name", i+1)
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("topic '%s' is declared more than once", t.Name)
+		}
+		seen[t.Name] = true
+		if t.Partitions <= 0 {
+			return fmt.Errorf("topic '%s' must have a positive number of partitions", t.Name)
+		}
+		if t.ReplicationFactor <= 0 {
+			return fmt.Errorf("topic '%s' must have a positive replication factor", t.Name)
+		}
+	}
+	return nil
+}
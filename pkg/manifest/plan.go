@@ -0,0 +1,359 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package manifest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+	"github.com/uraniumdawn/cinnamon/pkg/policy"
+)
+
+// ChangeKind is the kind of a planned change.
+type ChangeKind string
+
+const (
+	// Create creates a topic declared in the manifest that does not exist.
+	Create ChangeKind = "create"
+	// UpdateConfig sets config entries that differ from the manifest.
+	UpdateConfig ChangeKind = "update config"
+	// IncreasePartitions raises the partition count to the manifest.
+	IncreasePartitions ChangeKind = "increase partitions"
+	// Unmanaged reports a topic of the cluster missing from the manifest, it is never changed.
+	Unmanaged ChangeKind = "unmanaged"
+	// Invalid reports a difference that cannot be applied, e.g. fewer partitions
+	// or a topic violating the policy rules of the cluster.
+	Invalid ChangeKind = "invalid"
+)
+
+// LiveTopic is the state of a topic on the cluster.
+type LiveTopic struct {
+	Partitions        int
+	ReplicationFactor int
+	// Config holds the config entries of the topic, nil when not described.
+	Config map[string]string
+}
+
+// ConfigChange is a single config entry to set.
+type ConfigChange struct {
+	Name string
+	From string
+	To   string
+}
+
+// Change is a single step of a plan.
+type Change struct {
+	Kind  ChangeKind
+	Topic string
+	// Spec is the declared topic, empty for unmanaged topics.
+	Spec           TopicSpec
+	FromPartitions int
+	Config         []ConfigChange
+	// Reason explains why an Invalid change cannot be applied.
+	Reason string
+}
+
+// Actionable reports whether applying the plan executes the change.
+func (c Change) Actionable() bool {
+	return c.Kind == Create || c.Kind == UpdateConfig || c.Kind == IncreasePartitions
+}
+
+// Plan is the ordered list of changes turning the live state into the manifest.
+type Plan struct {
+	Changes []Change
+}
+
+// NewPlan diffs a manifest against the live topics of a cluster. Created topics
+// and edits of existing ones violating the policy rules of the cluster are
+// reported as invalid changes. Config entries a created topic does not declare
+// are checked with their value in defaults, see client.TopicDefaults.
+func NewPlan(
+	m *Manifest,
+	live map[string]LiveTopic,
+	rules []policy.Rule,
+	defaults map[string]string,
+) *Plan {
+	plan := &Plan{}
+	declared := make(map[string]bool, len(m.Topics))
+
+	for _, spec := range m.Topics {
+		declared[spec.Name] = true
+		current, ok := live[spec.Name]
+		if !ok {
+			plan.Changes = append(plan.Changes, Change{Kind: Create, Topic: spec.Name, Spec: spec})
+			plan.addViolations(spec, policy.Check(rules, policy.Topic{
+				Name:              spec.Name,
+				Partitions:        spec.Partitions,
+				ReplicationFactor: spec.ReplicationFactor,
+				Config:            policy.WithDefaults(spec.Config, defaults),
+			}))
+			continue
+		}
+
+		if spec.ReplicationFactor != current.ReplicationFactor {
+			plan.Changes = append(plan.Changes, Change{
+				Kind:  Invalid,
+				Topic: spec.Name,
+				Spec:  spec,
+				Reason: fmt.Sprintf(
+					"replication factor cannot be changed (%d -> %d)",
+					current.ReplicationFactor,
+					spec.ReplicationFactor,
+				),
+			})
+		}
+
+		switch {
+		case spec.Partitions < current.Partitions:
+			plan.Changes = append(plan.Changes, Change{
+				Kind:  Invalid,
+				Topic: spec.Name,
+				Spec:  spec,
+				Reason: fmt.Sprintf(
+					"partitions cannot be decreased (%d -> %d)",
+					current.Partitions,
+					spec.Partitions,
+				),
+			})
+		case spec.Partitions > current.Partitions:
+			plan.Changes = append(plan.Changes, Change{
+				Kind:           IncreasePartitions,
+				Topic:          spec.Name,
+				Spec:           spec,
+				FromPartitions: current.Partitions,
+			})
+		}
+
+		var config []ConfigChange
+		edited := make(map[string]string)
+		for _, name := range sortedKeys(spec.Config) {
+			if from := current.Config[name]; from != spec.Config[name] {
+				config = append(config, ConfigChange{Name: name, From: from, To: spec.Config[name]})
+				edited[name] = spec.Config[name]
+			}
+		}
+		if len(config) > 0 {
+			plan.Changes = append(plan.Changes, Change{
+				Kind:   UpdateConfig,
+				Topic:  spec.Name,
+				Spec:   spec,
+				Config: config,
+			})
+		}
+
+		plan.addViolations(spec, policy.CheckEdit(rules, policy.Topic{
+			Name:              spec.Name,
+			Partitions:        max(spec.Partitions, current.Partitions),
+			ReplicationFactor: current.ReplicationFactor,
+			Config:            current.Config,
+		}, current.Partitions, edited))
+	}
+
+	var unmanaged []string
+	for name := range live {
+		// Internal topics such as __consumer_offsets or _schemas are not reported.
+		if !declared[name] && !strings.HasPrefix(name, "_") {
+			unmanaged = append(unmanaged, name)
+		}
+	}
+	sort.Strings(unmanaged)
+	for _, name := range unmanaged {
+		plan.Changes = append(plan.Changes, Change{Kind: Unmanaged, Topic: name})
+	}
+
+	return plan
+}
+
+// addViolations adds an invalid change for every policy violation of a topic.
+func (p *Plan) addViolations(spec TopicSpec, violations []policy.Violation) {
+	for _, v := range violations {
+		p.Changes = append(p.Changes, Change{
+			Kind:   Invalid,
+			Topic:  spec.Name,
+			Spec:   spec,
+			Reason: fmt.Sprintf("violates policy '%s': %s is %s", v.Rule, v.Rule.Key, v.Actual),
+		})
+	}
+}
+
+// Count returns the number of changes of a kind.
+func (p *Plan) Count(kind ChangeKind) int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// HasChanges reports whether applying the plan changes the cluster.
+func (p *Plan) HasChanges() bool {
+	for _, c := range p.Changes {
+		if c.Actionable() {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the plan in the style of terraform: '+' creates, '~' updates,
+// '?' unmanaged topics and '!' changes that cannot be applied.
+func (p *Plan) String() string {
+	var sb strings.Builder
+	for _, c := range p.Changes {
+		switch c.Kind {
+		case Create:
+			sb.WriteString(fmt.Sprintf(
+				"+ create topic %s (partitions: %d, replication factor: %d)\n",
+				c.Topic, c.Spec.Partitions, c.Spec.ReplicationFactor,
+			))
+			for _, name := range sortedKeys(c.Spec.Config) {
+				sb.WriteString(fmt.Sprintf("    + %s = %q\n", name, c.Spec.Config[name]))
+			}
+		case IncreasePartitions:
+			sb.WriteString(fmt.Sprintf(
+				"~ increase partitions of topic %s: %d -> %d\n",
+				c.Topic, c.FromPartitions, c.Spec.Partitions,
+			))
+		case UpdateConfig:
+			sb.WriteString(fmt.Sprintf("~ update config of topic %s\n", c.Topic))
+			for _, cc := range c.Config {
+				sb.WriteString(fmt.Sprintf("    ~ %s: %q -> %q\n", cc.Name, cc.From, cc.To))
+			}
+		case Unmanaged:
+			sb.WriteString(fmt.Sprintf("? unmanaged topic %s\n", c.Topic))
+		case Invalid:
+			sb.WriteString(fmt.Sprintf("! topic %s: %s\n", c.Topic, c.Reason))
+		}
+	}
+
+	if !p.HasChanges() && p.Count(Invalid) == 0 {
+		sb.WriteString("No changes. The cluster matches the manifest.\n")
+	}
+	sb.WriteString(fmt.Sprintf(
+		"\nPlan: %d to create, %d to update config, %d to increase partitions, %d unmanaged, %d invalid.\n",
+		p.Count(Create),
+		p.Count(UpdateConfig),
+		p.Count(IncreasePartitions),
+		p.Count(Unmanaged),
+		p.Count(Invalid),
+	))
+	return sb.String()
+}
+
+// Live fetches the state of all topics of the cluster. Configs are described only
+// for topics declared in the manifest.
+func Live(c *client.Client, m *Manifest) (map[string]LiveTopic, error) {
+	resultCh := make(chan *client.TopicsResult, 1)
+	errorCh := make(chan error, 1)
+	c.Topics(resultCh, errorCh)
+
+	var topics *client.TopicsResult
+	select {
+	case topics = <-resultCh:
+	case err := <-errorCh:
+		return nil, err
+	case <-time.After(2 * c.Timeout):
+		return nil, fmt.Errorf("timeout while listing topics")
+	}
+
+	live := make(map[string]LiveTopic, len(topics.Result))
+	for name, metadata := range topics.Result {
+		t := LiveTopic{Partitions: len(metadata.Partitions)}
+		if len(metadata.Partitions) > 0 {
+			t.ReplicationFactor = len(metadata.Partitions[0].Replicas)
+		}
+		live[name] = t
+	}
+
+	for _, spec := range m.Topics {
+		t, ok := live[spec.Name]
+		if !ok {
+			continue
+		}
+		results, err := c.DescribeTopicConfig(spec.Name)
+		if err != nil {
+			return nil, err
+		}
+		t.Config = make(map[string]string)
+		for _, r := range *results {
+			for name, entry := range r.Config {
+				t.Config[name] = entry.Value
+			}
+		}
+		live[spec.Name] = t
+	}
+
+	return live, nil
+}
+
+// Apply executes the actionable changes of a plan in order and stops at the first
+// failure. A plan with invalid changes is refused as a whole. report is called
+// after every executed change.
+func Apply(c *client.Client, p *Plan, report func(Change, error)) error {
+	if n := p.Count(Invalid); n > 0 {
+		return fmt.Errorf("plan has %d invalid changes, fix the manifest first", n)
+	}
+
+	for _, change := range p.Changes {
+		var err error
+		switch change.Kind {
+		case Create:
+			spec := change.Spec
+			err = await(c, func(resultCh chan<- bool, errorCh chan<- error) {
+				c.CreateTopic(spec.Name, spec.Partitions, spec.ReplicationFactor, spec.Config, resultCh, errorCh)
+			})
+		case IncreasePartitions:
+			err = await(c, func(resultCh chan<- bool, errorCh chan<- error) {
+				c.CreatePartitions(change.Topic, change.Spec.Partitions, resultCh, errorCh)
+			})
+		case UpdateConfig:
+			config := make(map[string]string, len(change.Config))
+			for _, cc := range change.Config {
+				config[cc.Name] = cc.To
+			}
+			err = await(c, func(resultCh chan<- bool, errorCh chan<- error) {
+				c.UpdateTopicConfig(change.Topic, config, resultCh, errorCh)
+			})
+		default:
+			continue
+		}
+
+		report(change, err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// await runs an asynchronous client call and waits for its outcome.
+func await(c *client.Client, call func(chan<- bool, chan<- error)) error {
+	resultCh := make(chan bool, 1)
+	errorCh := make(chan error, 1)
+	call(resultCh, errorCh)
+
+	select {
+	case <-resultCh:
+		return nil
+	case err := <-errorCh:
+		return err
+	case <-time.After(2 * c.Timeout):
+		return fmt.Errorf("timeout while waiting for the cluster")
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
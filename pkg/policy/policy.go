@@ -0,0 +1,225 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+// Package policy evaluates per-cluster topic rules such as
+// `replication.factor >= 3` or `name =~ ^[a-z.-]+$`.
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Keys of a rule that refer to the topic itself rather than to a config entry.
+const (
+	KeyName              = "name"
+	KeyPartitions        = "partitions"
+	KeyReplicationFactor = "replication.factor"
+)
+
+var ruleRegex = regexp.MustCompile(`^\s*([A-Za-z0-9._-]+)\s*(>=|<=|==|!=|=~|>|<)\s*(.+?)\s*$`)
+
+// Rule is a single condition every topic of a cluster must satisfy.
+type Rule struct {
+	Key   string
+	Op    string
+	Value string
+	// number is the parsed value of the numeric comparisons.
+	number float64
+	// pattern is the compiled value of =~.
+	pattern *regexp.Regexp
+}
+
+// Topic is the state of a topic a rule is checked against.
+type Topic struct {
+	Name              string
+	Partitions        int
+	ReplicationFactor int
+	Config            map[string]string
+}
+
+// Violation is a rule a topic does not satisfy.
+type Violation struct {
+	Topic  string
+	Rule   Rule
+	Actual string
+}
+
+func (v Violation) Error() string {
+	return fmt.Sprintf("topic '%s' violates policy '%s': %s is %s", v.Topic, v.Rule, v.Rule.Key, v.Actual)
+}
+
+// Parse parses a rule of the form `<key> <op> <value>`. Keys are name, partitions,
+// replication.factor or a topic config entry. Ops are >=, <=, >, <, ==, != and =~,
+// which matches a regular expression.
+func Parse(s string) (Rule, error) {
+	match := ruleRegex.FindStringSubmatch(s)
+	if match == nil {
+		return Rule{}, fmt.Errorf("invalid policy rule '%s', expected '<key> <op> <value>'", s)
+	}
+
+	r := Rule{Key: match[1], Op: match[2], Value: match[3]}
+	switch r.Op {
+	case "=~":
+		pattern, err := regexp.Compile(r.Value)
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid pattern of policy rule '%s': %w", s, err)
+		}
+		r.pattern = pattern
+	case ">=", "<=", ">", "<":
+		number, err := strconv.ParseFloat(r.Value, 64)
+		if err != nil {
+			return Rule{}, fmt.Errorf("policy rule '%s' compares with a non-numeric value", s)
+		}
+		r.number = number
+	}
+	return r, nil
+}
+
+// ParseAll parses the rules of a cluster.
+func ParseAll(rules []string) ([]Rule, error) {
+	parsed := make([]Rule, 0, len(rules))
+	for _, s := range rules {
+		r, err := Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, r)
+	}
+	return parsed, nil
+}
+
+func (r Rule) String() string {
+	return fmt.Sprintf("%s %s %s", r.Key, r.Op, r.Value)
+}
+
+// IsConfig reports whether the rule refers to a topic config entry.
+func (r Rule) IsConfig() bool {
+	return r.Key != KeyName && r.Key != KeyPartitions && r.Key != KeyReplicationFactor
+}
+
+// Check returns the violation of the rule by a topic, if any. Rules on config entries
+// apply to the effective config of the topic; an entry missing from t.Config has no
+// value to check and is skipped, so new topics are checked with WithDefaults.
+func (r Rule) Check(t Topic) (Violation, bool) {
+	var actual string
+	switch r.Key {
+	case KeyName:
+		actual = t.Name
+	case KeyPartitions:
+		actual = strconv.Itoa(t.Partitions)
+	case KeyReplicationFactor:
+		actual = strconv.Itoa(t.ReplicationFactor)
+	default:
+		value, ok := t.Config[r.Key]
+		if !ok {
+			return Violation{}, false
+		}
+		actual = value
+	}
+
+	if !r.satisfied(actual) {
+		return Violation{t.Name, r, actual}, true
+	}
+	return Violation{}, false
+}
+
+func (r Rule) satisfied(actual string) bool {
+	switch r.Op {
+	case "=~":
+		return r.pattern.MatchString(actual)
+	case "==", "!=":
+		equal := actual == r.Value
+		a, errA := strconv.ParseFloat(actual, 64)
+		b, errB := strconv.ParseFloat(r.Value, 64)
+		if errA == nil && errB == nil {
+			equal = a == b
+		}
+		return equal == (r.Op == "==")
+	}
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(actual), 64)
+	if err != nil {
+		return false
+	}
+	switch r.Op {
+	case ">=":
+		return n >= r.number
+	case "<=":
+		return n <= r.number
+	case ">":
+		return n > r.number
+	default:
+		return n < r.number
+	}
+}
+
+// ConfigKeys returns the config entries the rules refer to.
+func ConfigKeys(rules []Rule) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, r := range rules {
+		if r.IsConfig() && !seen[r.Key] {
+			seen[r.Key] = true
+			keys = append(keys, r.Key)
+		}
+	}
+	return keys
+}
+
+// WithDefaults returns the effective config of a new topic: its config with the
+// cluster defaults of the entries it does not set.
+func WithDefaults(config, defaults map[string]string) map[string]string {
+	effective := make(map[string]string, len(config)+len(defaults))
+	for name, value := range defaults {
+		effective[name] = value
+	}
+	for name, value := range config {
+		effective[name] = value
+	}
+	return effective
+}
+
+// Check returns the violations of all rules by a topic.
+func Check(rules []Rule, t Topic) []Violation {
+	var violations []Violation
+	for _, r := range rules {
+		if v, ok := r.Check(t); ok {
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// CheckEdit returns the violations of the rules by an edit of a topic. The edited
+// topic has the new partition count and the effective config before the edit;
+// only the rules on what the edit changes are enforced, so that a topic already
+// violating other rules can still be edited.
+func CheckEdit(
+	rules []Rule,
+	edited Topic,
+	partitions int,
+	editedConfig map[string]string,
+) []Violation {
+	config := make(map[string]string, len(edited.Config)+len(editedConfig))
+	for name, value := range edited.Config {
+		config[name] = value
+	}
+	for name, value := range editedConfig {
+		config[name] = value
+	}
+	edited.Config = config
+
+	var violations []Violation
+	for _, v := range Check(rules, edited) {
+		_, changed := editedConfig[v.Rule.Key]
+		if (v.Rule.IsConfig() && changed) ||
+			(v.Rule.Key == KeyPartitions && edited.Partitions != partitions) {
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
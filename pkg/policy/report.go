@@ -0,0 +1,93 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+)
+
+// ReportWorkers bounds the number of topics whose configs are described concurrently.
+const ReportWorkers = 8
+
+// Report contains the violations of the rules by the topics of a cluster.
+type Report struct {
+	Topics     int
+	Violations []Violation
+}
+
+// NewReport checks every topic of the cluster against the rules. Topic configs are
+// described one topic at a time with at most ReportWorkers concurrent requests.
+// Internal topics and topics prefixed with '_' are skipped.
+func NewReport(c *client.Client, rules []Rule) (*Report, error) {
+	resultCh := make(chan *client.TopicsResult, 1)
+	errorCh := make(chan error, 1)
+	c.Topics(resultCh, errorCh)
+
+	var topics *client.TopicsResult
+	select {
+	case topics = <-resultCh:
+	case err := <-errorCh:
+		return nil, err
+	case <-time.After(2 * c.Timeout):
+		return nil, fmt.Errorf("timeout while listing topics")
+	}
+
+	var checked []Topic
+	for name, metadata := range topics.Result {
+		if strings.HasPrefix(name, "_") {
+			continue
+		}
+		t := Topic{Name: name, Partitions: len(metadata.Partitions), Config: make(map[string]string)}
+		if len(metadata.Partitions) > 0 {
+			t.ReplicationFactor = len(metadata.Partitions[0].Replicas)
+		}
+		checked = append(checked, t)
+	}
+
+	errs := make([]error, len(checked))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(ReportWorkers, len(checked)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results, err := c.DescribeTopicConfig(checked[i].Name)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				for _, r := range *results {
+					for name, entry := range r.Config {
+						checked[i].Config[name] = entry.Value
+					}
+				}
+			}
+		}()
+	}
+	for i := range checked {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	report := &Report{Topics: len(checked)}
+	for i, t := range checked {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("failed to describe config of topic '%s': %w", t.Name, errs[i])
+		}
+		report.Violations = append(report.Violations, Check(rules, t)...)
+	}
+	sort.SliceStable(report.Violations, func(i, j int) bool {
+		return report.Violations[i].Topic < report.Violations[j].Topic
+	})
+	return report, nil
+}
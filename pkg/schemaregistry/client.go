@@ -7,6 +7,7 @@ package schemaregistry
 
 import (
 	"sort"
+	"sync"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/schemaregistry"
 	"github.com/rs/zerolog/log"
@@ -18,6 +19,8 @@ import (
 type Client struct {
 	ClusterName string
 	schemaregistry.Client
+	schemas map[int]*schema
+	mx      sync.RWMutex
 }
 
 // SchemaResult contains the schema metadata.
@@ -25,18 +28,58 @@ type SchemaResult struct {
 	Metadata schemaregistry.SchemaMetadata
 }
 
+// SubjectsResult contains schema subjects with the schema type of their latest version.
+type SubjectsResult struct {
+	Subjects []string
+	// Types holds the known schema types by subject, filled by SubjectTypes.
+	Types map[string]string
+}
+
+// SubjectType is the schema type of the latest version of a subject.
+type SubjectType struct {
+	Subject string
+	Type    string
+}
+
+// VersionsResult contains the versions of a subject and its schema type.
+type VersionsResult struct {
+	Versions   []int
+	SchemaType string
+}
+
+// subjectsConcurrency bounds the number of concurrent schema type lookups.
+const subjectsConcurrency = 8
+
+// SchemaType returns the schema type, the registry omits it for Avro schemas.
+func SchemaType(info schemaregistry.SchemaInfo) string {
+	if info.SchemaType == "" {
+		return SchemaTypeAvro
+	}
+	return info.SchemaType
+}
+
 // NewSchemaRegistryClient creates a new Schema Registry client with the given configuration.
 func NewSchemaRegistryClient(config *config.SchemaRegistryConfig) (*Client, error) {
+	resolved, err := config.Resolved()
+	if err != nil {
+		log.Err(err).Msg("failed to resolve schema registry config")
+		return nil, err
+	}
+
 	client, err := schemaregistry.NewClient(schemaregistry.NewConfigWithBasicAuthentication(
-		config.SchemaRegistryURL,
-		config.SchemaRegistryUsername,
-		config.SchemaRegistryPassword))
+		resolved.SchemaRegistryURL,
+		resolved.SchemaRegistryUsername,
+		resolved.SchemaRegistryPassword))
 	if err != nil {
 		log.Err(err).Msg("failed to connect to schema registry")
 		return nil, err
 	}
 
-	return &Client{config.Name, client}, nil
+	return &Client{
+		ClusterName: config.Name,
+		Client:      client,
+		schemas:     make(map[int]*schema),
+	}, nil
 }
 
 func (client *Client) DescribeSchemaRegistry(resultChan chan<- []string, errorChan chan<- error) {
@@ -50,22 +93,63 @@ func (client *Client) DescribeSchemaRegistry(resultChan chan<- []string, errorCh
 	}()
 }
 
-// Subjects retrieves all schema subjects from the Schema Registry.
-func (client *Client) Subjects(resultChan chan<- []string, errorChan chan<- error) {
+// Subjects retrieves all schema subjects from the Schema Registry. Their schema
+// types are left empty, they take a request per subject, see SubjectTypes.
+func (client *Client) Subjects(resultChan chan<- *SubjectsResult, errorChan chan<- error) {
 	go func() {
 		subjects, err := client.GetAllSubjects()
 		if err != nil {
 			errorChan <- err
 			return
 		}
-		resultChan <- subjects
+		resultChan <- &SubjectsResult{Subjects: subjects, Types: make(map[string]string)}
+	}()
+}
+
+// SubjectTypes looks up the schema type of the latest version of the subjects, a
+// few at a time, and sends each to resultChan, which is closed once all are sent.
+// Lookups stop when stop is closed. Failed lookups are logged and skipped.
+func (client *Client) SubjectTypes(
+	subjects []string,
+	resultChan chan<- SubjectType,
+	stop <-chan struct{},
+) {
+	go func() {
+		defer close(resultChan)
+
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, subjectsConcurrency)
+	lookups:
+		for _, subject := range subjects {
+			select {
+			case sem <- struct{}{}:
+			case <-stop:
+				break lookups
+			}
+			wg.Add(1)
+			go func(subject string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				metadata, err := client.GetLatestSchemaMetadata(subject)
+				if err != nil {
+					log.Warn().Err(err).Str("subject", subject).Msg("failed to get schema type")
+					return
+				}
+				select {
+				case resultChan <- SubjectType{subject, SchemaType(metadata.SchemaInfo)}:
+				case <-stop:
+				}
+			}(subject)
+		}
+		wg.Wait()
 	}()
 }
 
 // VersionsBySubject retrieves all versions for a specific subject.
 func (client *Client) VersionsBySubject(
 	subject string,
-	resultChan chan<- []int,
+	resultChan chan<- *VersionsResult,
 	errorChan chan<- error,
 ) {
 	go func() {
@@ -75,7 +159,13 @@ func (client *Client) VersionsBySubject(
 			return
 		}
 		sort.Sort(sort.Reverse(sort.IntSlice(versions)))
-		resultChan <- versions
+
+		metadata, err := client.GetLatestSchemaMetadata(subject)
+		if err != nil {
+			errorChan <- err
+			return
+		}
+		resultChan <- &VersionsResult{versions, SchemaType(metadata.SchemaInfo)}
 	}()
 }
 
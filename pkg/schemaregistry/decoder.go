@@ -0,0 +1,103 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package schemaregistry
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+const (
+	// magicByte prefixes every record serialized in the Confluent wire format.
+	magicByte = 0
+	// wireHeaderSize is the size of the magic byte and the 4-byte schema ID.
+	wireHeaderSize = 5
+)
+
+// Schema types as reported by the Schema Registry, an empty type means Avro.
+const (
+	SchemaTypeAvro       = "AVRO"
+	SchemaTypeProtobuf   = "PROTOBUF"
+	SchemaTypeJSONSchema = "JSON"
+)
+
+// schema is a writer schema resolved by ID.
+type schema struct {
+	schemaType string
+	codec      *goavro.Codec
+}
+
+// ParseWireFormat splits a Confluent wire format payload into the schema ID
+// and the serialized body. ok is false when the payload is not in the wire format.
+func ParseWireFormat(data []byte) (id int, body []byte, ok bool) {
+	if len(data) < wireHeaderSize || data[0] != magicByte {
+		return 0, nil, false
+	}
+	return int(binary.BigEndian.Uint32(data[1:wireHeaderSize])), data[wireHeaderSize:], true
+}
+
+// Decode renders a Confluent wire format payload as JSON using its writer schema.
+func (client *Client) Decode(data []byte) (string, error) {
+	id, body, ok := ParseWireFormat(data)
+	if !ok {
+		return "", fmt.Errorf("payload is not in the Confluent wire format")
+	}
+
+	s, err := client.resolve(id)
+	if err != nil {
+		return "", err
+	}
+
+	switch s.schemaType {
+	case SchemaTypeAvro:
+		native, _, err := s.codec.NativeFromBinary(body)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode Avro payload with schema %d: %w", id, err)
+		}
+		text, err := s.codec.TextualFromNative(nil, native)
+		if err != nil {
+			return "", fmt.Errorf("failed to render Avro payload with schema %d: %w", id, err)
+		}
+		return string(text), nil
+	case SchemaTypeJSONSchema:
+		return string(body), nil
+	default:
+		return "", fmt.Errorf("decoding of %s payloads is not supported", s.schemaType)
+	}
+}
+
+// resolve returns the writer schema for an ID, fetching it from the registry on first use.
+func (client *Client) resolve(id int) (*schema, error) {
+	client.mx.RLock()
+	s, ok := client.schemas[id]
+	client.mx.RUnlock()
+	if ok {
+		return s, nil
+	}
+
+	info, err := client.GetBySubjectAndID("", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch schema %d: %w", id, err)
+	}
+
+	s = &schema{schemaType: SchemaType(info)}
+
+	if s.schemaType == SchemaTypeAvro {
+		if len(info.References) > 0 {
+			return nil, fmt.Errorf("schema %d uses references which are not supported", id)
+		}
+		s.codec, err = goavro.NewCodec(info.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Avro schema %d: %w", id, err)
+		}
+	}
+
+	client.mx.Lock()
+	client.schemas[id] = s
+	client.mx.Unlock()
+	return s, nil
+}
@@ -0,0 +1,415 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+	"github.com/uraniumdawn/cinnamon/pkg/util"
+)
+
+const (
+	// GetACLsEventType is the event type for fetching ACL bindings.
+	GetACLsEventType EventType = "acls:get"
+)
+
+// ACLsChannel is the channel for ACL events.
+var ACLsChannel = make(chan Event)
+
+var (
+	aclResourceTypes = []kafka.ResourceType{
+		kafka.ResourceTopic,
+		kafka.ResourceGroup,
+		kafka.ResourceBroker,
+	}
+	aclPatternTypes = []kafka.ResourcePatternType{
+		kafka.ResourcePatternTypeLiteral,
+		kafka.ResourcePatternTypePrefixed,
+	}
+	aclOperations = []kafka.ACLOperation{
+		kafka.ACLOperationAll,
+		kafka.ACLOperationRead,
+		kafka.ACLOperationWrite,
+		kafka.ACLOperationCreate,
+		kafka.ACLOperationDelete,
+		kafka.ACLOperationAlter,
+		kafka.ACLOperationDescribe,
+		kafka.ACLOperationClusterAction,
+		kafka.ACLOperationDescribeConfigs,
+		kafka.ACLOperationAlterConfigs,
+		kafka.ACLOperationIdempotentWrite,
+	}
+	aclPermissionTypes = []kafka.ACLPermissionType{
+		kafka.ACLPermissionTypeAllow,
+		kafka.ACLPermissionTypeDeny,
+	}
+)
+
+// defaultACLFilter matches all ACL bindings of the cluster.
+var defaultACLFilter = client.NewACLFilter("", kafka.ResourceAny, kafka.ResourcePatternTypeAny)
+
+// RunACLsEventHandler processes ACL events from the channel.
+func (app *App) RunACLsEventHandler(ctx context.Context, in chan Event) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				log.Debug().Msg("shutting down ACLs event handler")
+				return
+			case event := <-in:
+				// Events of this handler read the selected cluster.
+				if !app.isClusterSelected(app.Selected) {
+					SendStatusWithDefaultTTL("[red]to perform operation, select cluster")
+					continue
+				}
+				switch event.Type {
+				case GetACLsEventType:
+					filter := defaultACLFilter
+					if f, ok := event.Payload.Data.(kafka.ACLBindingFilter); ok {
+						filter = f
+					}
+					pageName := util.BuildPageKey(app.Selected.Cluster.Name, ACLs)
+					force := event.Payload.Force
+					_, found := app.Cache.Get(pageName)
+					if found && !force {
+						app.SwitchToPage(pageName)
+					} else {
+						app.ACLs(filter)
+					}
+				}
+			}
+		}
+	}()
+}
+
+// ACLs fetches and displays the ACL bindings matching a filter.
+func (app *App) ACLs(filter kafka.ACLBindingFilter) {
+	resultCh := make(chan *client.ACLsResult)
+	errorCh := make(chan error)
+
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	SendStatusInfinite("getting ACLs")
+	c.ACLs(filter, resultCh, errorCh)
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
+
+	go func() {
+		for {
+			select {
+			case result := <-resultCh:
+				app.QueueUpdateDraw(func() {
+					table := app.NewACLsTable(result)
+					app.AddToPagesRegistry(
+						util.BuildPageKey(app.Selected.Cluster.Name, ACLs),
+						table,
+						ACLsPageMenu, false,
+					)
+					ClearStatus()
+				})
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to list ACLs")
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to list ACLs: %s", err.Error()))
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while listing ACLs")
+				SendStatusWithDefaultTTL("[red]timeout while listing ACLs")
+				return
+			}
+		}
+	}()
+}
+
+// NewACLsTable creates a table displaying ACL bindings.
+func (app *App) NewACLsTable(result *client.ACLsResult) *tview.Table {
+	filter := result.Filter
+
+	table := tview.NewTable()
+	table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetBorderPadding(0, 0, 1, 0)
+	table.SetSelectedStyle(
+		tcell.StyleDefault.Foreground(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.FgColor),
+		).Background(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.BgColor),
+		),
+	)
+
+	principal := filter.Principal
+	if principal == "" {
+		principal = "any principal"
+	}
+	table.SetTitle(util.BuildTitle(
+		ACLs,
+		principal,
+		strings.ToLower(filter.Type.String()),
+		strings.ToLower(filter.ResourcePatternType.String()),
+		"["+strconv.Itoa(len(result.Bindings))+"]",
+	))
+
+	headers := []string{
+		"Principal", "Host", "Operation", "Permission", "Resource Type", "Pattern", "Resource",
+	}
+	for i, h := range headers {
+		table.SetCell(0, i, tview.NewTableCell(h).
+			SetTextColor(tcell.GetColor(app.Colors.Cinnamon.Label.FgColor)).
+			SetSelectable(false))
+	}
+
+	for i, b := range result.Bindings {
+		row := i + 1
+		table.SetCell(row, 0, tview.NewTableCell(b.Principal))
+		table.SetCell(row, 1, tview.NewTableCell(b.Host))
+		table.SetCell(row, 2, tview.NewTableCell(b.Operation.String()))
+		table.SetCell(row, 3, tview.NewTableCell(b.PermissionType.String()))
+		table.SetCell(row, 4, tview.NewTableCell(b.Type.String()))
+		table.SetCell(row, 5, tview.NewTableCell(b.ResourcePatternType.String()))
+		table.SetCell(row, 6, tview.NewTableCell(b.Name).SetExpansion(1))
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlU {
+			Publish(ACLsChannel, GetACLsEventType, Payload{filter, true})
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'f' {
+			app.FilterACLs(filter)
+			return nil
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'c' {
+			app.CreateACL(filter)
+			return nil
+		}
+
+		if event.Key() == tcell.KeyCtrlD {
+			row, _ := table.GetSelection()
+			if row >= 1 && row <= len(result.Bindings) {
+				app.DeleteACL(result.Bindings[row-1], filter)
+			}
+			return nil
+		}
+
+		return event
+	})
+
+	return table
+}
+
+// FilterACLs shows the form to filter ACL bindings by principal, resource type and pattern type.
+func (app *App) FilterACLs(current kafka.ACLBindingFilter) {
+	width := 40
+
+	principal := app.NewInputField("User:alice, empty for all", width).
+		SetText(current.Principal)
+
+	resourceTypes := append([]kafka.ResourceType{kafka.ResourceAny}, aclResourceTypes...)
+	resourceType := app.newEnumDropDown(width, len(resourceTypes), func(i int) string {
+		return resourceTypes[i].String()
+	})
+	for i, t := range resourceTypes {
+		if t == current.Type {
+			resourceType.SetCurrentOption(i)
+		}
+	}
+
+	patternTypes := append(
+		[]kafka.ResourcePatternType{kafka.ResourcePatternTypeAny, kafka.ResourcePatternTypeMatch},
+		aclPatternTypes...,
+	)
+	patternType := app.newEnumDropDown(width, len(patternTypes), func(i int) string {
+		return patternTypes[i].String()
+	})
+	for i, t := range patternTypes {
+		if t == current.ResourcePatternType {
+			patternType.SetCurrentOption(i)
+		}
+	}
+
+	app.showForm(
+		FilterACLs,
+		" Filter ACLs ",
+		[]string{"Principal:", "Resource type:", "Pattern type:"},
+		[]tview.Primitive{principal, resourceType, patternType},
+		func() bool {
+			rt, _ := resourceType.GetCurrentOption()
+			pt, _ := patternType.GetCurrentOption()
+			filter := client.NewACLFilter(
+				strings.TrimSpace(principal.GetText()),
+				resourceTypes[rt],
+				patternTypes[pt],
+			)
+			Publish(ACLsChannel, GetACLsEventType, Payload{filter, true})
+			return true
+		},
+	)
+}
+
+// CreateACL shows the form to create an ACL binding.
+func (app *App) CreateACL(filter kafka.ACLBindingFilter) {
+	width := 40
+
+	principal := app.NewInputField("User:alice", width)
+	host := app.NewInputField("*", width)
+	resourceType := app.newEnumDropDown(width, len(aclResourceTypes), func(i int) string {
+		return aclResourceTypes[i].String()
+	})
+	resourceName := app.NewInputField("Topic or group name, kafka-cluster for broker", width)
+	patternType := app.newEnumDropDown(width, len(aclPatternTypes), func(i int) string {
+		return aclPatternTypes[i].String()
+	})
+	operation := app.newEnumDropDown(width, len(aclOperations), func(i int) string {
+		return aclOperations[i].String()
+	})
+	permission := app.newEnumDropDown(width, len(aclPermissionTypes), func(i int) string {
+		return aclPermissionTypes[i].String()
+	})
+
+	app.showForm(
+		CreateACL,
+		" Create ACL ",
+		[]string{
+			"Principal:", "Host:", "Resource type:", "Resource name:",
+			"Pattern type:", "Operation:", "Permission:",
+		},
+		[]tview.Primitive{
+			principal, host, resourceType, resourceName, patternType, operation, permission,
+		},
+		func() bool {
+			rt, _ := resourceType.GetCurrentOption()
+			pt, _ := patternType.GetCurrentOption()
+			op, _ := operation.GetCurrentOption()
+			perm, _ := permission.GetCurrentOption()
+
+			binding := kafka.ACLBinding{
+				Type:                aclResourceTypes[rt],
+				Name:                strings.TrimSpace(resourceName.GetText()),
+				ResourcePatternType: aclPatternTypes[pt],
+				Principal:           strings.TrimSpace(principal.GetText()),
+				Host:                strings.TrimSpace(host.GetText()),
+				Operation:           aclOperations[op],
+				PermissionType:      aclPermissionTypes[perm],
+			}
+			if binding.Host == "" {
+				binding.Host = "*"
+			}
+			if !strings.Contains(binding.Principal, ":") {
+				SendStatusWithDefaultTTL("[red]principal must have the form Type:name, e.g. User:alice")
+				return false
+			}
+			if binding.Name == "" {
+				SendStatusWithDefaultTTL("[red]resource name must not be empty")
+				return false
+			}
+
+			app.CreateACLResultHandler(binding, filter)
+			return true
+		},
+	)
+}
+
+// CreateACLResultHandler creates an ACL binding and refreshes the ACLs page.
+func (app *App) CreateACLResultHandler(binding kafka.ACLBinding, filter kafka.ACLBindingFilter) {
+	resultCh := make(chan bool)
+	errorCh := make(chan error)
+
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	SendStatusInfinite("creating ACL")
+	c.CreateACL(binding, resultCh, errorCh)
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
+
+	go func() {
+		for {
+			select {
+			case <-resultCh:
+				SendStatus("ACL has been created", 2*time.Second, false)
+				Publish(ACLsChannel, GetACLsEventType, Payload{filter, true})
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to create ACL")
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to create ACL: %s", err.Error()))
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while creating ACL")
+				SendStatusWithDefaultTTL("[red]timeout while creating ACL")
+				return
+			}
+		}
+	}()
+}
+
+// DeleteACL asks for confirmation before deleting an ACL binding.
+func (app *App) DeleteACL(binding kafka.ACLBinding, filter kafka.ACLBindingFilter) {
+	message := fmt.Sprintf(
+		"ACL [red::b]%s %s %s on %s %s[-::-] will be deleted. Confirm?",
+		tview.Escape(binding.PermissionType.String()),
+		tview.Escape(binding.Principal),
+		tview.Escape(binding.Operation.String()),
+		tview.Escape(binding.Type.String()),
+		tview.Escape(binding.Name),
+	)
+	app.confirmDestructive(DeleteACL, " Confirm Deletion ", message, binding.Name, func() {
+		app.DeleteACLResultHandler(binding, filter)
+	})
+}
+
+// DeleteACLResultHandler deletes an ACL binding and refreshes the ACLs page.
+func (app *App) DeleteACLResultHandler(binding kafka.ACLBinding, filter kafka.ACLBindingFilter) {
+	resultCh := make(chan bool)
+	errorCh := make(chan error)
+
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	SendStatusInfinite("deleting ACL")
+	c.DeleteACL(binding, resultCh, errorCh)
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
+
+	go func() {
+		for {
+			select {
+			case <-resultCh:
+				SendStatus("ACL has been deleted", 2*time.Second, false)
+				Publish(ACLsChannel, GetACLsEventType, Payload{filter, true})
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to delete ACL")
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to delete ACL: %s", err.Error()))
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while deleting ACL")
+				SendStatusWithDefaultTTL("[red]timeout while deleting ACL")
+				return
+			}
+		}
+	}()
+}
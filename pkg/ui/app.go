@@ -7,9 +7,11 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
@@ -40,15 +42,67 @@ const (
 	DeleteTopic      = "Delete Topic"
 	EditTopic        = "Edit Topic"
 	CliTemplates     = "CLI Templates"
+	Messages         = "Messages"
+	ConsumeMessages  = "Consume Messages"
+	ProduceMessage   = "Produce Message"
+	ResetOffsets     = "Reset Offsets"
+
+	ConfirmResetOffsets = "Confirm Reset Offsets"
+
+	DeleteConsumerGroups = "Delete Consumer Groups"
+	DeleteRecords        = "Delete Records"
+	ConfirmDeleteRecords = "Confirm Delete Records"
+
+	ElectLeaders           = "Elect Leaders"
+	ConfirmUncleanElection = "Confirm Unclean Election"
+	ConfirmClusterElection = "Confirm Cluster Election"
+	LeaderElection         = "Leader election"
+
+	ACLs       = "ACLs"
+	FilterACLs = "Filter ACLs"
+	CreateACL  = "Create ACL"
+	DeleteACL  = "Delete ACL"
+
+	Users            = "Users"
+	UpsertCredential = "Upsert Credential"
+	DeleteCredential = "Delete Credential"
+
+	BrokerDefaults     = "Broker defaults"
+	EditBrokerConfig   = "Edit Broker Config"
+	DeleteBrokerConfig = "Delete Broker Config"
+
+	LagOverview = "Lag overview"
+	LagWatch    = "Lag watch"
+
+	Health = "Health"
+
+	PlanManifest     = "Plan Manifest"
+	TopicsPlan       = "Topics plan"
+	ConfirmApplyPlan = "Confirm Apply Plan"
+
+	ExportSnapshot = "Export Snapshot"
+
+	CompareClusters = "Compare Clusters"
+	ClusterDiff     = "Cluster diff"
+	CopyTopic       = "Copy Topic"
+
+	PolicyViolations = "Policy violations"
+
+	AuditLog = "Audit log"
 )
 
 type App struct {
 	*tview.Application
-	Layout                *Layout
-	Cache                 *cache.Cache
-	Clusters              map[string]*config.ClusterConfig
-	SchemaRegistries      map[string]*config.SchemaRegistryConfig
-	KafkaClients          map[string]*client.Client
+	Layout           *Layout
+	Cache            *cache.Cache
+	Clusters         map[string]*config.ClusterConfig
+	SchemaRegistries map[string]*config.SchemaRegistryConfig
+	KafkaClients     map[string]*client.Client
+	// kafkaClientsMx guards KafkaClients, which event handlers read while the UI
+	// goroutine adds clients.
+	kafkaClientsMx        sync.RWMutex
+	KafkaConsumers        map[string]*client.Consumer
+	KafkaProducers        map[string]*client.Producer
 	SchemaRegistryClients map[string]*schemaregistry.Client
 	Selected              Selected
 	Config                *config.Config
@@ -61,8 +115,123 @@ type Selected struct {
 	SchemaRegistry *config.SchemaRegistryConfig
 }
 
-func (app *App) GetCurrentKafkaClient() *client.Client {
-	return app.KafkaClients[app.Selected.Cluster.Name]
+// GetCurrentKafkaClient returns the admin client of the selected cluster, or an
+// error when no cluster is selected or its client has not been created.
+func (app *App) GetCurrentKafkaClient() (*client.Client, error) {
+	if !app.isClusterSelected(app.Selected) {
+		return nil, errors.New("to perform operation, select cluster")
+	}
+	c, ok := app.getKafkaClient(app.Selected.Cluster.Name)
+	if !ok {
+		return nil, fmt.Errorf("cluster '%s' is not connected", app.Selected.Cluster.Name)
+	}
+	return c, nil
+}
+
+// getKafkaClient returns the admin client of a cluster if it has been created.
+func (app *App) getKafkaClient(name string) (*client.Client, bool) {
+	app.kafkaClientsMx.RLock()
+	defer app.kafkaClientsMx.RUnlock()
+	c, ok := app.KafkaClients[name]
+	return c, ok
+}
+
+// WithKafkaClient calls use on the UI goroutine with the admin client of a
+// configured cluster, without selecting it. Resolving the secret references of a
+// cluster may run commands, so a client used for the first time is created in the
+// background; failures are reported in the status line and use is not called.
+func (app *App) WithKafkaClient(name string, use func(*client.Client)) {
+	app.withKafkaClient(name, use, nil)
+}
+
+// withKafkaClient is WithKafkaClient that also calls failed, unless nil, on the UI
+// goroutine when the client cannot be created.
+func (app *App) withKafkaClient(name string, use func(*client.Client), failed func(error)) {
+	if c, ok := app.getKafkaClient(name); ok {
+		use(c)
+		return
+	}
+
+	cluster, ok := app.Clusters[name]
+	if !ok {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]cluster '%s' is not configured", name))
+		return
+	}
+
+	SendStatusInfinite(fmt.Sprintf("connecting to cluster %s", name))
+	go func() {
+		c, err := client.NewClient(cluster, app.Config.GetAPICallTimeout())
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				log.Error().Err(err).Str("cluster", name).Msg("failed to create admin client")
+				SendStatusWithDefaultTTL(fmt.Sprintf(
+					"[red]failed to connect to cluster '%s': %s",
+					name,
+					err.Error(),
+				))
+				if failed != nil {
+					failed(err)
+				}
+				return
+			}
+			ClearStatus()
+			use(app.addKafkaClient(name, c))
+		})
+	}()
+}
+
+// addKafkaClient keeps a new client of a cluster and watches its connection. When
+// another client was created for the cluster meanwhile, the new one is closed.
+func (app *App) addKafkaClient(name string, c *client.Client) *client.Client {
+	app.kafkaClientsMx.Lock()
+	if existing, ok := app.KafkaClients[name]; ok {
+		app.kafkaClientsMx.Unlock()
+		c.Close()
+		return existing
+	}
+	app.KafkaClients[name] = c
+	app.kafkaClientsMx.Unlock()
+
+	c.Watch(func(previous client.ConnectionState, current client.Connection) {
+		app.ConnectionChanged(name, previous, current)
+	})
+	return c
+}
+
+// GetCurrentKafkaConsumer returns the consumer of the selected cluster,
+// creating it on first use.
+func (app *App) GetCurrentKafkaConsumer() (*client.Consumer, error) {
+	name := app.Selected.Cluster.Name
+	if consumer, ok := app.KafkaConsumers[name]; ok {
+		return consumer, nil
+	}
+
+	consumer, err := client.NewConsumer(app.Selected.Cluster, app.Config.GetAPICallTimeout())
+	if err != nil {
+		return nil, err
+	}
+	app.KafkaConsumers[name] = consumer
+	return consumer, nil
+}
+
+// GetCurrentKafkaProducer returns the producer of the selected cluster using
+// the given partitioner, creating it on first use.
+func (app *App) GetCurrentKafkaProducer(partitioner string) (*client.Producer, error) {
+	key := util.BuildPageKey(app.Selected.Cluster.Name, partitioner)
+	if producer, ok := app.KafkaProducers[key]; ok {
+		return producer, nil
+	}
+
+	producer, err := client.NewProducer(
+		app.Selected.Cluster,
+		partitioner,
+		app.Config.GetAPICallTimeout(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	app.KafkaProducers[key] = producer
+	return producer, nil
 }
 
 func (app *App) GetCurrentSchemaRegistryClient() *schemaregistry.Client {
@@ -72,19 +241,21 @@ func (app *App) GetCurrentSchemaRegistryClient() *schemaregistry.Client {
 	return app.SchemaRegistryClients[app.Selected.SchemaRegistry.Name]
 }
 
-func NewApp() *App {
+// NewApp creates the application from the config files. The TUI is not started
+// when the config cannot be loaded.
+func NewApp() (*App, error) {
 	InitLogger()
 
 	cfg, err := config.LoadAppConfig()
 	if err != nil {
-		log.Fatal().Err(err).Msg("failed to initialize config")
-		os.Exit(1)
+		log.Error().Err(err).Msg("failed to initialize config")
+		return nil, err
 	}
 
 	colors, err := config.LoadColorConfig()
 	if err != nil {
-		log.Fatal().Err(err).Msg("failed to load color config")
-		os.Exit(1)
+		log.Error().Err(err).Msg("failed to load color config")
+		return nil, fmt.Errorf("failed to load color config: %w", err)
 	}
 
 	app := &App{
@@ -93,12 +264,14 @@ func NewApp() *App {
 		Clusters:              util.ToClustersMap(cfg),
 		SchemaRegistries:      util.ToSchemaRegistryMap(cfg),
 		KafkaClients:          make(map[string]*client.Client),
+		KafkaConsumers:        make(map[string]*client.Consumer),
+		KafkaProducers:        make(map[string]*client.Producer),
 		SchemaRegistryClients: make(map[string]*schemaregistry.Client),
 		Config:                cfg,
 		Colors:                colors,
 	}
 
-	return app
+	return app, nil
 }
 
 func InitLogger() {
@@ -142,20 +315,22 @@ func (app *App) Run() {
 	app.RunTopicsEventHandler(ctx, TopicsChannel)
 	app.RunCgroupsEventHandler(ctx, CgroupsChannel)
 	app.RunSubjectsEventHandler(ctx, SubjectsChannel)
+	app.RunACLsEventHandler(ctx, ACLsChannel)
+	app.RunUsersEventHandler(ctx, UsersChannel)
 
 	registry := NewPagesRegistry(app.Colors)
 	app.Layout = NewLayout(registry, app.Colors)
 
 	for _, c := range app.Config.Cinnamon.Clusters {
 		if c.Selected {
-			app.SelectCluster(c, false)
+			app.SelectCluster(c, false, nil)
 			break // Assuming only one can be selected
 		}
 	}
 
 	for _, sr := range app.Config.Cinnamon.SchemaRegistries {
 		if sr.Selected {
-			app.SelectSchemaRegistry(sr, false)
+			app.SelectSchemaRegistry(sr, false, nil)
 			break // Assuming only one can be selected
 		}
 	}
@@ -216,7 +391,11 @@ func (app *App) isSchemaRegistrySelected(selected Selected) bool {
 	return selected.SchemaRegistry != nil
 }
 
-func (app *App) SelectCluster(cluster *config.ClusterConfig, save bool) {
+// SelectCluster makes a cluster the selected one and creates its client in the
+// background on first use, then calls selected unless nil. The selection is kept
+// when the client cannot be created, operations then report the cluster as not
+// connected until it is selected again.
+func (app *App) SelectCluster(cluster *config.ClusterConfig, save bool, selected func()) {
 	if save {
 		for _, c := range app.Config.Cinnamon.Clusters {
 			c.Selected = c.Name == cluster.Name
@@ -228,43 +407,67 @@ func (app *App) SelectCluster(cluster *config.ClusterConfig, save bool) {
 
 	app.Selected.Cluster = cluster
 	app.Layout.SetSelected(app.Selected.Cluster, app.Selected.SchemaRegistry)
+	app.Layout.Menu.SetReadOnly(cluster.ReadOnly)
+	app.Layout.SetConnection(client.Connection{State: client.Connecting})
 
-	_, exists := app.KafkaClients[cluster.Name]
-	if !exists {
-		var err error
-		timeout := app.Config.GetAPICallTimeout()
-		newClient, err := client.NewClient(cluster, timeout)
-		if err != nil {
-			log.Error().Err(err).Msg("failed to create admin client")
-			os.Exit(1)
+	app.withKafkaClient(cluster.Name, func(admin *client.Client) {
+		if app.Selected.Cluster == cluster {
+			app.Layout.SetConnection(admin.Connection())
 		}
-		app.KafkaClients[cluster.Name] = newClient
-	}
+		if selected != nil {
+			selected()
+		}
+	}, func(err error) {
+		if app.Selected.Cluster == cluster {
+			app.Layout.SetConnection(client.Connection{State: client.Unreachable, Err: err})
+		}
+	})
 }
 
-func (app *App) SelectSchemaRegistry(sr *config.SchemaRegistryConfig, save bool) {
-	if save {
-		for _, r := range app.Config.Cinnamon.SchemaRegistries {
-			r.Selected = r.Name == sr.Name
+// SelectSchemaRegistry makes a schema registry the selected one, creating its client
+// in the background on first use, then calls selected unless nil. The selection is
+// kept when the client cannot be created.
+func (app *App) SelectSchemaRegistry(sr *config.SchemaRegistryConfig, save bool, selected func()) {
+	apply := func() {
+		if save {
+			for _, r := range app.Config.Cinnamon.SchemaRegistries {
+				r.Selected = r.Name == sr.Name
+			}
+			if err := app.Config.Save(); err != nil {
+				log.Error().Err(err).Msg("failed to save config after schema registry selection")
+			}
 		}
-		if err := app.Config.Save(); err != nil {
-			log.Error().Err(err).Msg("failed to save config after schema registry selection")
+
+		app.Selected.SchemaRegistry = sr
+		app.Layout.SetSelected(app.Selected.Cluster, app.Selected.SchemaRegistry)
+		if selected != nil {
+			selected()
 		}
 	}
 
-	app.Selected.SchemaRegistry = sr
-	app.Layout.SetSelected(app.Selected.Cluster, app.Selected.SchemaRegistry)
+	if _, exists := app.SchemaRegistryClients[sr.Name]; exists {
+		apply()
+		return
+	}
 
-	_, exists := app.SchemaRegistryClients[sr.Name]
-	if !exists {
-		var err error
+	SendStatusInfinite(fmt.Sprintf("connecting to schema registry %s", sr.Name))
+	go func() {
 		newClient, err := schemaregistry.NewSchemaRegistryClient(sr)
-		if err != nil {
-			log.Error().Err(err).Msg("failed to create admin client")
-			os.Exit(1)
-		}
-		app.SchemaRegistryClients[sr.Name] = newClient
-	}
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				log.Error().Err(err).Str("registry", sr.Name).Msg("failed to create schema registry client")
+				SendStatusWithDefaultTTL(fmt.Sprintf(
+					"[red]failed to connect to schema registry '%s': %s",
+					sr.Name,
+					err.Error(),
+				))
+				return
+			}
+			ClearStatus()
+			app.SchemaRegistryClients[sr.Name] = newClient
+			apply()
+		})
+	}()
 }
 
 func (app *App) NewDescription(title string) *tview.TextView {
@@ -280,3 +483,18 @@ func (app *App) NewDescription(title string) *tview.TextView {
 	desc.SetTextColor(tcell.GetColor(app.Colors.Cinnamon.Foreground))
 	return desc
 }
+
+// NewInputField creates an input field styled like the other form inputs.
+func (app *App) NewInputField(placeholder string, width int) *tview.InputField {
+	return tview.NewInputField().
+		SetFieldWidth(width).
+		SetPlaceholder(placeholder).
+		SetPlaceholderStyle(
+			tcell.StyleDefault.Foreground(
+				tcell.GetColor(app.Colors.Cinnamon.Foreground),
+			).Background(
+				tcell.GetColor(app.Colors.Cinnamon.Background),
+			)).
+		SetPlaceholderTextColor(tcell.GetColor(app.Colors.Cinnamon.Placeholder)).
+		SetFieldBackgroundColor(tcell.GetColor(app.Colors.Cinnamon.Label.BgColor))
+}
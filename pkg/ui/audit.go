@@ -0,0 +1,154 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/audit"
+	"github.com/uraniumdawn/cinnamon/pkg/util"
+)
+
+const auditTimeFormat = "2006-01-02 15:04:05"
+
+// AuditLog reads the local audit log and displays its entries, newest first.
+func (app *App) AuditLog() {
+	SendStatusInfinite("reading audit log")
+
+	go func() {
+		entries, err := audit.Read()
+		if err != nil {
+			log.Error().Err(err).Msg("failed to read audit log")
+			SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to read audit log: %s", err.Error()))
+			return
+		}
+		slices.Reverse(entries)
+
+		app.QueueUpdateDraw(func() {
+			table := app.NewAuditLogTable(entries)
+			title := util.BuildTitle(AuditLog, fmt.Sprintf("[%d]", len(entries)))
+			table.SetTitle(title)
+			app.AddToPagesRegistry(AuditLog, table, AuditLogPageMenu, true)
+
+			app.AssignSearch(func(text string) {
+				var filtered []audit.Entry
+				for _, e := range entries {
+					if e.Matches(text) {
+						filtered = append(filtered, e)
+					}
+				}
+				populateAuditLogTable(table, filtered)
+				util.SetSearchableTableTitle(table, title, text)
+				table.ScrollToBeginning()
+			})
+
+			ClearStatus()
+		})
+	}()
+}
+
+// NewAuditLogTable creates a table of audit log entries. The entries shown are
+// kept in the table so that a filtered row can be described.
+func (app *App) NewAuditLogTable(entries []audit.Entry) *tview.Table {
+	table := tview.NewTable()
+	table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetBorderPadding(0, 0, 1, 0)
+	table.SetSelectedStyle(
+		tcell.StyleDefault.Foreground(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.FgColor),
+		).Background(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.BgColor),
+		),
+	)
+
+	headers := []string{"Time", "User", "Cluster", "Resource", "Action", "Outcome", "Details"}
+	for i, h := range headers {
+		table.SetCell(0, i, tview.NewTableCell(h).
+			SetTextColor(tcell.GetColor(app.Colors.Cinnamon.Label.FgColor)).
+			SetSelectable(false))
+	}
+	populateAuditLogTable(table, entries)
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlU {
+			app.AuditLog()
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'd' {
+			row, _ := table.GetSelection()
+			if e, ok := table.GetCell(row, 0).GetReference().(audit.Entry); ok {
+				app.AuditEntry(e)
+			}
+		}
+
+		return event
+	})
+
+	return table
+}
+
+func populateAuditLogTable(table *tview.Table, entries []audit.Entry) {
+	for row := table.GetRowCount() - 1; row > 0; row-- {
+		table.RemoveRow(row)
+	}
+
+	for i, e := range entries {
+		outcome := tview.NewTableCell(e.Outcome).SetTextColor(tcell.ColorGreen)
+		if e.Outcome != audit.OK {
+			outcome.SetTextColor(tcell.ColorRed)
+		}
+
+		table.SetCell(i+1, 0, tview.NewTableCell(e.Time.Local().Format(auditTimeFormat)).
+			SetReference(e))
+		table.SetCell(i+1, 1, tview.NewTableCell(tview.Escape(e.User)))
+		table.SetCell(i+1, 2, tview.NewTableCell(tview.Escape(e.Cluster)))
+		table.SetCell(i+1, 3, tview.NewTableCell(e.Resource))
+		table.SetCell(i+1, 4, tview.NewTableCell(e.Action))
+		table.SetCell(i+1, 5, outcome)
+		table.SetCell(i+1, 6, tview.NewTableCell(tview.Escape(auditDetails(e))).
+			SetExpansion(1))
+	}
+}
+
+// auditDetails formats the parameters and the error of an entry on a single line.
+func auditDetails(e audit.Entry) string {
+	var parts []string
+	for _, name := range slices.Sorted(maps.Keys(e.Params)) {
+		value, _ := json.Marshal(e.Params[name])
+		parts = append(parts, fmt.Sprintf("%s=%s", name, value))
+	}
+	if e.Error != "" {
+		parts = append(parts, "error: "+e.Error)
+	}
+	return strings.Join(parts, " ")
+}
+
+// AuditEntry displays an audit log entry with its parameters.
+func (app *App) AuditEntry(e audit.Entry) {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to describe audit entry: %s", err.Error()))
+		return
+	}
+
+	timestamp := e.Time.Local().Format(auditTimeFormat)
+	desc := app.NewDescription(util.BuildTitle(AuditLog, timestamp))
+	desc.SetText(tview.Escape(string(data)))
+	app.AddToPagesRegistry(
+		util.BuildPageKey(AuditLog, timestamp, e.Resource, e.Action),
+		desc,
+		AuditEntryPageMenu, false,
+	)
+}
@@ -0,0 +1,256 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+	"github.com/uraniumdawn/cinnamon/pkg/util"
+)
+
+// NewBrokerConfigTable creates a table of the config entries of a broker or, for
+// client.ClusterDefaultBroker, of the cluster-wide defaults. Read-only entries are
+// dimmed, dynamic overrides are highlighted and other non-default entries use the
+// label color.
+func (app *App) NewBrokerConfigTable(node NodeIDURLPair, result *client.ResourceResult) *tview.Table {
+	var entries []kafka.ConfigEntryResult
+	for _, r := range result.Results {
+		for _, e := range r.Config {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+
+	table := tview.NewTable()
+	table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetBorderPadding(0, 0, 1, 0)
+	table.SetSelectedStyle(
+		tcell.StyleDefault.Foreground(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.FgColor),
+		).Background(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.BgColor),
+		),
+	)
+	table.SetTitle(nodeTitle(node, "["+strconv.Itoa(len(entries))+"]"))
+
+	for i, h := range []string{"Name", "Value", "Source", "Read-only"} {
+		table.SetCell(0, i, tview.NewTableCell(h).
+			SetTextColor(tcell.GetColor(app.Colors.Cinnamon.Label.FgColor)).
+			SetSelectable(false))
+	}
+
+	for i, e := range entries {
+		color := tcell.GetColor(app.Colors.Cinnamon.Foreground)
+		switch {
+		case e.IsReadOnly:
+			color = tcell.GetColor(app.Colors.Cinnamon.Placeholder)
+		case isDynamicBrokerConfig(e):
+			color = tcell.GetColor(app.Colors.Cinnamon.Title)
+		case e.Source != kafka.ConfigSourceDefault:
+			color = tcell.GetColor(app.Colors.Cinnamon.Label.FgColor)
+		}
+
+		value := e.Value
+		if e.IsSensitive {
+			value = "******"
+		}
+
+		row := i + 1
+		table.SetCell(row, 0, tview.NewTableCell(e.Name).SetTextColor(color))
+		table.SetCell(row, 1, tview.NewTableCell(value).SetTextColor(color).SetMaxWidth(60))
+		table.SetCell(row, 2, tview.NewTableCell(e.Source.String()).SetTextColor(color))
+		table.SetCell(row, 3, tview.NewTableCell(strconv.FormatBool(e.IsReadOnly)).
+			SetTextColor(color).
+			SetExpansion(1))
+	}
+
+	selected := func() (kafka.ConfigEntryResult, bool) {
+		row, _ := table.GetSelection()
+		if row < 1 || row > len(entries) {
+			return kafka.ConfigEntryResult{}, false
+		}
+		return entries[row-1], true
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlU {
+			Publish(NodesChannel, GetNodeEventType, Payload{node, true})
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'e' {
+			entry, ok := selected()
+			if !ok {
+				return nil
+			}
+			if entry.IsReadOnly {
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]'%s' is read-only", entry.Name))
+				return nil
+			}
+			app.EditBrokerConfig(node, entry.Name, entry.Value)
+			return nil
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'c' {
+			app.EditBrokerConfig(node, "", "")
+			return nil
+		}
+
+		if event.Key() == tcell.KeyCtrlD {
+			entry, ok := selected()
+			if !ok {
+				return nil
+			}
+			if !isOverride(node, entry) {
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]'%s' has no override to delete", entry.Name))
+				return nil
+			}
+			app.DeleteBrokerConfig(node, entry.Name)
+			return nil
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'D' {
+			Publish(NodesChannel, GetNodeEventType, Payload{clusterDefaults, false})
+			return nil
+		}
+
+		return event
+	})
+
+	return table
+}
+
+// EditBrokerConfig shows the form to set a config entry of a broker or of the
+// cluster-wide default. An empty name allows setting an entry that is not listed.
+func (app *App) EditBrokerConfig(node NodeIDURLPair, name, value string) {
+	width := 40
+
+	entry := app.NewInputField("Config name", width).SetText(name)
+	input := app.NewInputField("Value", width).SetText(value)
+
+	app.showForm(
+		EditBrokerConfig,
+		nodeTitle(node, "edit"),
+		[]string{"Name:", "Value:"},
+		[]tview.Primitive{entry, input},
+		func() bool {
+			n := strings.TrimSpace(entry.GetText())
+			if n == "" {
+				SendStatusWithDefaultTTL("[red]config name must not be empty")
+				return false
+			}
+			app.UpdateBrokerConfigResultHandler(
+				node, n, input.GetText(), kafka.AlterConfigOpTypeSet,
+			)
+			return true
+		},
+	)
+}
+
+// DeleteBrokerConfig asks for confirmation before deleting a config override.
+func (app *App) DeleteBrokerConfig(node NodeIDURLPair, name string) {
+	scope := "broker " + node.ID
+	if node.ID == client.ClusterDefaultBroker {
+		scope = "the cluster-wide default"
+	}
+
+	message := fmt.Sprintf(
+		"Override of [red::b]%s[-::-] on %s will be deleted. Confirm?",
+		tview.Escape(name),
+		scope,
+	)
+	app.confirmDestructive(DeleteBrokerConfig, " Confirm Deletion ", message, name, func() {
+		app.UpdateBrokerConfigResultHandler(node, name, "", kafka.AlterConfigOpTypeDelete)
+	})
+}
+
+// UpdateBrokerConfigResultHandler applies a config change and refreshes the node page.
+func (app *App) UpdateBrokerConfigResultHandler(
+	node NodeIDURLPair,
+	name, value string,
+	op kafka.AlterConfigOpType,
+) {
+	resultCh := make(chan bool)
+	errorCh := make(chan error)
+
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	SendStatusInfinite("updating broker config")
+	c.UpdateBrokerConfig(node.ID, name, value, op, resultCh, errorCh)
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
+
+	go func() {
+		for {
+			select {
+			case <-resultCh:
+				SendStatus(
+					fmt.Sprintf("broker config '%s' has been updated", tview.Escape(name)),
+					2*time.Second,
+					false,
+				)
+				Publish(NodesChannel, GetNodeEventType, Payload{node, true})
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to update broker config")
+				SendStatusWithDefaultTTL(
+					fmt.Sprintf("[red]failed to update broker config: %s", err.Error()),
+				)
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while updating broker config")
+				SendStatusWithDefaultTTL("[red]timeout while updating broker config")
+				return
+			}
+		}
+	}()
+}
+
+// clusterDefaults addresses the cluster-wide default of dynamic broker configs.
+var clusterDefaults = NodeIDURLPair{ID: client.ClusterDefaultBroker}
+
+func isDynamicBrokerConfig(e kafka.ConfigEntryResult) bool {
+	return e.Source == kafka.ConfigSourceDynamicBroker ||
+		e.Source == kafka.ConfigSourceDynamicDefaultBroker
+}
+
+// isOverride reports whether the entry is set on the resource the page shows,
+// so deleting it reverts to the next source in precedence.
+func isOverride(node NodeIDURLPair, e kafka.ConfigEntryResult) bool {
+	if node.ID == client.ClusterDefaultBroker {
+		return e.Source == kafka.ConfigSourceDynamicDefaultBroker
+	}
+	return e.Source == kafka.ConfigSourceDynamicBroker
+}
+
+func nodeTitle(node NodeIDURLPair, parts ...string) string {
+	if node.ID == client.ClusterDefaultBroker {
+		return util.BuildTitle(append([]string{BrokerDefaults}, parts...)...)
+	}
+	return util.BuildTitle(append([]string{Node, node.URL, node.ID}, parts...)...)
+}
+
+func nodePageKey(cluster string, node NodeIDURLPair) string {
+	if node.ID == client.ClusterDefaultBroker {
+		return util.BuildPageKey(cluster, BrokerDefaults)
+	}
+	return util.BuildPageKey(cluster, Node, node.ID)
+}
@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"github.com/gdamore/tcell/v2"
@@ -39,6 +41,11 @@ func (app *App) RunCgroupsEventHandler(ctx context.Context, in chan Event) {
 				log.Debug().Msg("shutting down cgroups event handler")
 				return
 			case event := <-in:
+				// Events of this handler read the selected cluster.
+				if !app.isClusterSelected(app.Selected) {
+					SendStatusWithDefaultTTL("[red]to perform operation, select cluster")
+					continue
+				}
 				switch event.Type {
 				case GetCgroupsEventType:
 					pageName := util.BuildPageKey(app.Selected.Cluster.Name, ConsumerGroups)
@@ -64,6 +71,16 @@ func (app *App) RunCgroupsEventHandler(ctx context.Context, in chan Event) {
 					} else {
 						app.ConsumerGroup(consumerGroup)
 					}
+
+				case GetLagOverviewEventType:
+					pageName := util.BuildPageKey(app.Selected.Cluster.Name, LagOverview)
+					force := event.Payload.Force
+					_, found := app.Cache.Get(pageName)
+					if found && !force {
+						app.SwitchToPage(pageName)
+					} else {
+						app.LagOverview()
+					}
 				}
 			}
 		}
@@ -75,7 +92,11 @@ func (app *App) ConsumerGroups() {
 	resultCh := make(chan *client.ConsumerGroupsResult)
 	errorCh := make(chan error)
 
-	c := app.GetCurrentKafkaClient()
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
 	SendStatusInfinite("getting consumer groups")
 	c.ConsumerGroups(resultCh, errorCh)
 	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
@@ -86,6 +107,7 @@ func (app *App) ConsumerGroups() {
 			case groups := <-resultCh:
 				app.QueueUpdateDraw(func() {
 					table := app.NewGroupsTable(groups)
+					marked := make(map[string]bool)
 					title := util.BuildTitle(ConsumerGroups,
 						"["+strconv.Itoa(len(groups.Valid))+"]")
 					table.SetTitle(title)
@@ -111,11 +133,47 @@ func (app *App) ConsumerGroups() {
 								Payload{groupName, false},
 							)
 						}
+
+						if event.Key() == tcell.KeyRune && event.Rune() == 'O' {
+							Publish(CgroupsChannel, GetLagOverviewEventType, Payload{nil, false})
+						}
+
+						if event.Key() == tcell.KeyRune && event.Rune() == ' ' {
+							row, _ := table.GetSelection()
+							groupName := table.GetCell(row, 0).Text
+							if groupName != "" {
+								marked[groupName] = !marked[groupName]
+								app.markGroupsTable(table, marked)
+								table.Select(min(row+1, table.GetRowCount()-1), 0)
+							}
+							return nil
+						}
+
+						if event.Key() == tcell.KeyCtrlD {
+							var names []string
+							for groupName, ok := range marked {
+								if ok {
+									names = append(names, groupName)
+								}
+							}
+							if len(names) == 0 {
+								row, _ := table.GetSelection()
+								if groupName := table.GetCell(row, 0).Text; groupName != "" {
+									names = append(names, groupName)
+								}
+							}
+							if len(names) > 0 {
+								sort.Strings(names)
+								app.DeleteConsumerGroups(names)
+							}
+							return nil
+						}
 						return event
 					})
 
 					app.AssignSearch(func(text string) {
 						filterConsumerGroupsTable(table, groups.Valid, text)
+						app.markGroupsTable(table, marked)
 						util.SetSearchableTableTitle(table, title, text)
 						table.ScrollToBeginning()
 					})
@@ -145,7 +203,11 @@ func (app *App) ConsumerGroup(name string) {
 	resultCh := make(chan *client.DescribeConsumerGroupResult)
 	errorCh := make(chan error)
 
-	c := app.GetCurrentKafkaClient()
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
 	SendStatusInfinite("getting consumer group description")
 	c.DescribeConsumerGroup(name, resultCh, errorCh)
 	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
@@ -163,6 +225,16 @@ func (app *App) ConsumerGroup(name string) {
 						if event.Key() == tcell.KeyCtrlU {
 							Publish(CgroupsChannel, GetCgroupEventType, Payload{name, true})
 						}
+
+						if event.Key() == tcell.KeyRune && event.Rune() == 'R' {
+							app.ResetOffsets(name)
+							return nil
+						}
+
+						if event.Key() == tcell.KeyRune && event.Rune() == 'w' {
+							app.WatchConsumerGroup(name)
+							return nil
+						}
 						return event
 					})
 					app.AddToPagesRegistry(
@@ -172,7 +244,7 @@ func (app *App) ConsumerGroup(name string) {
 							name,
 						),
 						desc,
-						FinalPageMenu, false,
+						ConsumerGroupPageMenu, false,
 					)
 					ClearStatus()
 				})
@@ -194,6 +266,82 @@ func (app *App) ConsumerGroup(name string) {
 	}()
 }
 
+// DeleteConsumerGroups asks for confirmation before deleting consumer groups.
+func (app *App) DeleteConsumerGroups(groups []string) {
+	escaped := make([]string, len(groups))
+	for i, g := range groups {
+		escaped[i] = tview.Escape(g)
+	}
+
+	// Deleting several groups at once is confirmed with the name of the cluster.
+	resource := app.Selected.Cluster.Name
+	if len(groups) == 1 {
+		resource = groups[0]
+	}
+	message := fmt.Sprintf(
+		"Consumer groups [red::b]%s[-::-] will be deleted. Confirm?",
+		strings.Join(escaped, ", "),
+	)
+	app.confirmDestructive(DeleteConsumerGroups, " Confirm Deletion ", message, resource, func() {
+		app.DeleteConsumerGroupsResultHandler(groups)
+	})
+}
+
+// DeleteConsumerGroupsResultHandler deletes consumer groups and reports groups that were refused.
+func (app *App) DeleteConsumerGroupsResultHandler(groups []string) {
+	resultCh := make(chan *client.DeleteConsumerGroupsResult)
+	errorCh := make(chan error)
+
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	SendStatusInfinite("deleting consumer groups")
+	c.DeleteConsumerGroups(groups, resultCh, errorCh)
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
+
+	go func() {
+		for {
+			select {
+			case result := <-resultCh:
+				if len(result.Errors) > 0 {
+					log.Error().Msg(result.String())
+					SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", tview.Escape(result.String())))
+				} else {
+					SendStatus(result.String(), 2*time.Second, false)
+				}
+				Publish(CgroupsChannel, GetCgroupsEventType, Payload{nil, true})
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to delete consumer groups")
+				SendStatusWithDefaultTTL(
+					fmt.Sprintf("[red]failed to delete consumer groups: %s", err.Error()),
+				)
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while deleting consumer groups")
+				SendStatusWithDefaultTTL("[red]timeout while deleting consumer groups")
+				return
+			}
+		}
+	}()
+}
+
+// markGroupsTable highlights the consumer groups marked for a bulk operation.
+func (app *App) markGroupsTable(table *tview.Table, marked map[string]bool) {
+	for row := 0; row < table.GetRowCount(); row++ {
+		cell := table.GetCell(row, 0)
+		if marked[cell.Text] {
+			cell.SetTextColor(tcell.GetColor(app.Colors.Cinnamon.Title))
+		} else {
+			cell.SetTextColor(tview.Styles.PrimaryTextColor)
+		}
+	}
+}
+
 // NewGroupsTable creates a table displaying consumer groups.
 func (app *App) NewGroupsTable(groups *client.ConsumerGroupsResult) *tview.Table {
 	table := tview.NewTable()
@@ -16,6 +16,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/uraniumdawn/cinnamon/pkg/shell"
 
+	"github.com/uraniumdawn/cinnamon/pkg/audit"
 	"github.com/uraniumdawn/cinnamon/pkg/util"
 )
 
@@ -35,7 +36,12 @@ func (app *App) CliTemplates(topicName string) {
 		),
 	)
 
-	bootstrap := app.Selected.Cluster.GetBootstrapServers()
+	bootstrap, err := app.Selected.Cluster.GetBootstrapServers()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to resolve bootstrap servers")
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
 	if bootstrap == "" {
 		SendStatusWithDefaultTTL("[red]bootstrap.servers not found in cluster config")
 		return
@@ -87,7 +93,12 @@ func (app *App) CliTemplates(topicName string) {
 }
 
 func (app *App) ExecuteCliCommand(topicName, commandTemplate string) {
-	bootstrap := app.Selected.Cluster.GetBootstrapServers()
+	bootstrap, err := app.Selected.Cluster.GetBootstrapServers()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to resolve bootstrap servers")
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
 	if bootstrap == "" {
 		SendStatusWithDefaultTTL("[red]bootstrap servers not configured")
 		log.Error().Msg("bootstrap servers not configured")
@@ -95,6 +106,7 @@ func (app *App) ExecuteCliCommand(topicName, commandTemplate string) {
 	}
 
 	command := util.BuildCliCommand(commandTemplate, bootstrap, topicName)
+	cluster := app.Selected.Cluster.Name
 
 	rc := make(chan string, 100)
 	errCh := make(chan string, 10)
@@ -196,6 +208,15 @@ func (app *App) ExecuteCliCommand(topicName, commandTemplate string) {
 
 	// Execute command through shell to support pipes, redirects, etc.
 	args := []string{"sh", "-c", command}
+	// The start is recorded up front so the audit log keeps the command even
+	// when cinnamon exits before the process does.
+	// The command is recorded as its template, bootstrap servers and secrets of the
+	// template are left out of the log.
+	template := audit.RedactCommand(commandTemplate)
+	audit.Record(cluster, "cli_command", "start", audit.Params{
+		"template": template,
+		"topic":    topicName,
+	}, nil)
 	go shell.Execute(args, rc, errCh, sig, processDone)
 
 	// Single goroutine to handle all output and process termination
@@ -241,6 +262,16 @@ func (app *App) ExecuteCliCommand(topicName, commandTemplate string) {
 			}
 		}
 
+		var outcome error
+		if exitCode != 0 {
+			outcome = fmt.Errorf("exit code %d", exitCode)
+		}
+		audit.Record(cluster, "cli_command", "exit", audit.Params{
+			"template":  template,
+			"topic":     topicName,
+			"exit_code": exitCode,
+		}, outcome)
+
 		// Show final status message based on exit code
 		switch {
 		case exitCode == 0:
@@ -0,0 +1,392 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+	"github.com/uraniumdawn/cinnamon/pkg/manifest"
+	"github.com/uraniumdawn/cinnamon/pkg/policy"
+	"github.com/uraniumdawn/cinnamon/pkg/util"
+)
+
+// CompareClusters shows the form choosing the two clusters whose topics are compared.
+// The selected cluster is offered on the left.
+func (app *App) CompareClusters() {
+	width := 40
+	names := make([]string, len(app.Config.Cinnamon.Clusters))
+	for i, c := range app.Config.Cinnamon.Clusters {
+		names[i] = c.Name
+	}
+	if len(names) < 2 {
+		SendStatusWithDefaultTTL("[red]at least two clusters must be configured to compare")
+		return
+	}
+
+	left := app.newEnumDropDown(width, len(names), func(i int) string { return names[i] })
+	right := app.newEnumDropDown(width, len(names), func(i int) string { return names[i] })
+	right.SetCurrentOption(1)
+	if app.isClusterSelected(app.Selected) {
+		for i, name := range names {
+			if name == app.Selected.Cluster.Name {
+				left.SetCurrentOption(i)
+				right.SetCurrentOption((i + 1) % len(names))
+			}
+		}
+	}
+
+	app.showForm(
+		CompareClusters,
+		" Compare Clusters ",
+		[]string{"Left", "Right"},
+		[]tview.Primitive{left, right},
+		func() bool {
+			l, _ := left.GetCurrentOption()
+			r, _ := right.GetCurrentOption()
+			if l == r {
+				SendStatusWithDefaultTTL("[red]choose two different clusters")
+				return false
+			}
+			app.ClusterDiff(names[l], names[r])
+			return true
+		},
+	)
+}
+
+// ClusterDiff loads the topics of two clusters at once and displays the topics
+// missing on either side and the topics whose layout or overridden configs differ.
+func (app *App) ClusterDiff(left, right string) {
+	app.WithKafkaClient(left, func(leftClient *client.Client) {
+		app.WithKafkaClient(right, func(rightClient *client.Client) {
+			app.compareClusters(left, right, leftClient, rightClient)
+		})
+	})
+}
+
+func (app *App) compareClusters(left, right string, leftClient, rightClient *client.Client) {
+	leftCh := make(chan []client.TopicSnapshot, 1)
+	rightCh := make(chan []client.TopicSnapshot, 1)
+	errorCh := make(chan error, 2)
+
+	SendStatusInfinite(fmt.Sprintf("comparing clusters %s and %s", left, right))
+	leftClient.TopicLayout(leftCh, errorCh)
+	rightClient.TopicLayout(rightCh, errorCh)
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
+
+	go func() {
+		defer cancel()
+		var leftTopics, rightTopics []client.TopicSnapshot
+		for received := 0; received < 2; received++ {
+			select {
+			case leftTopics = <-leftCh:
+			case rightTopics = <-rightCh:
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to compare clusters")
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to compare clusters: %s", err.Error()))
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while comparing clusters")
+				SendStatusWithDefaultTTL("[red]timeout while comparing clusters")
+				return
+			}
+		}
+
+		diffs := client.DiffTopics(leftTopics, rightTopics)
+		app.QueueUpdateDraw(func() {
+			app.AddToPagesRegistry(
+				util.BuildPageKey(ClusterDiff, left, right),
+				app.NewClusterDiffView(left, right, diffs),
+				ClusterDiffPageMenu, false,
+			)
+			ClearStatus()
+		})
+	}()
+}
+
+// NewClusterDiffView creates the table of differing topics with the config
+// differences of the selected topic below it. Identical topics are only counted.
+func (app *App) NewClusterDiffView(left, right string, diffs []client.TopicDiff) *tview.Flex {
+	table := tview.NewTable()
+	table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetBorderPadding(0, 0, 1, 0)
+	table.SetSelectedStyle(
+		tcell.StyleDefault.Foreground(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.FgColor),
+		).Background(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.BgColor),
+		),
+	)
+
+	details := app.NewDescription(" Config ")
+
+	headers := []string{"Topic", "Status", left, right, "Differences"}
+	for i, h := range headers {
+		table.SetCell(0, i, tview.NewTableCell(h).
+			SetTextColor(tcell.GetColor(app.Colors.Cinnamon.Label.FgColor)).
+			SetSelectable(false))
+	}
+
+	identical := 0
+	rows := make(map[int]client.TopicDiff)
+	row := 1
+	for _, d := range diffs {
+		if d.Kind == client.Identical {
+			identical++
+			continue
+		}
+
+		color := tcell.ColorYellow
+		if d.Kind != client.Mismatch {
+			color = tcell.ColorRed
+		}
+		table.SetCell(row, 0, tview.NewTableCell(d.Topic))
+		table.SetCell(row, 1, tview.NewTableCell(string(d.Kind)).SetTextColor(color))
+		table.SetCell(row, 2, tview.NewTableCell(topicLayout(d.Left)))
+		table.SetCell(row, 3, tview.NewTableCell(topicLayout(d.Right)))
+		table.SetCell(row, 4, tview.NewTableCell(diffSummary(d)).SetExpansion(1))
+		rows[row] = d
+		row++
+	}
+
+	table.SetTitle(util.BuildTitle(
+		ClusterDiff,
+		left+" ↔ "+right,
+		fmt.Sprintf("%d differing", len(rows)),
+		fmt.Sprintf("%d identical", identical),
+	))
+
+	table.SetSelectionChangedFunc(func(r, _ int) {
+		details.SetText(configDiffText(left, right, rows[r]))
+	})
+	details.SetText(configDiffText(left, right, rows[1]))
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlU {
+			app.ClusterDiff(left, right)
+		}
+
+		if event.Key() == tcell.KeyRune && (event.Rune() == '>' || event.Rune() == '<') {
+			r, _ := table.GetSelection()
+			d, ok := rows[r]
+			if !ok {
+				return event
+			}
+			refresh := func() { app.ClusterDiff(left, right) }
+			if event.Rune() == '>' {
+				app.CopyTopic(d.Left, left, right, refresh)
+			} else {
+				app.CopyTopic(d.Right, right, left, refresh)
+			}
+		}
+
+		return event
+	})
+
+	return tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(table, 0, 2, true).
+		AddItem(details, 0, 1, false)
+}
+
+// CopyTopic plans copying a topic definition to another cluster: a missing topic is
+// created with the same partitions, replication factor and overridden configs, an
+// existing one gets the overridden configs and, when it has fewer, the partitions.
+// The plan is applied after a confirmation, then done is called.
+func (app *App) CopyTopic(source *client.TopicSnapshot, from, to string, done func()) {
+	if source == nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]topic does not exist on cluster '%s'", from))
+		return
+	}
+	cluster, ok := app.Clusters[to]
+	if !ok {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]cluster '%s' is not configured", to))
+		return
+	}
+	if cluster.ReadOnly {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]cluster '%s' is read-only", to))
+		return
+	}
+	// The copy must satisfy the policy rules of the target cluster.
+	rules, err := policy.ParseAll(cluster.Policies)
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	app.WithKafkaClient(to, func(target *client.Client) {
+		app.planTopicCopy(source, from, to, target, rules, done)
+	})
+}
+
+// planTopicCopy plans the copy of a topic to the target client and asks for a
+// confirmation when the plan has changes.
+func (app *App) planTopicCopy(
+	source *client.TopicSnapshot,
+	from, to string,
+	target *client.Client,
+	rules []policy.Rule,
+	done func(),
+) {
+	spec := manifest.TopicSpec{
+		Name:              source.Name,
+		Partitions:        len(source.Partitions),
+		ReplicationFactor: source.ReplicationFactor,
+		Config:            make(map[string]string),
+	}
+	for name, value := range source.Config {
+		// Sensitive values are masked in the description and cannot be copied.
+		if value != client.SensitiveValue {
+			spec.Config[name] = value
+		}
+	}
+	m := &manifest.Manifest{Topics: []manifest.TopicSpec{spec}}
+
+	SendStatusInfinite(fmt.Sprintf("planning copy of topic %s to %s", spec.Name, to))
+	go func() {
+		all, err := manifest.Live(target, m)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to plan topic copy")
+			SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to plan topic copy: %s", err.Error()))
+			return
+		}
+		// Other topics of the target are not part of the copy.
+		live := make(map[string]manifest.LiveTopic)
+		if t, ok := all[spec.Name]; ok {
+			live[spec.Name] = t
+		}
+		defaults, err := target.TopicDefaults(policy.ConfigKeys(rules))
+		if err != nil {
+			log.Error().Err(err).Msg("failed to get topic defaults")
+			SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to get topic defaults: %s", err.Error()))
+			return
+		}
+		plan := manifest.NewPlan(m, live, rules, defaults)
+
+		for _, c := range plan.Changes {
+			if c.Kind == manifest.Invalid {
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]cannot copy topic %s: %s", spec.Name, c.Reason))
+				return
+			}
+		}
+		if !plan.HasChanges() {
+			SendStatusWithDefaultTTL(fmt.Sprintf("topic %s already matches on %s", spec.Name, to))
+			return
+		}
+
+		app.QueueUpdateDraw(func() {
+			ClearStatus()
+			app.ConfirmCopyTopic(plan, from, to, done)
+		})
+	}()
+}
+
+// ConfirmCopyTopic shows the plan of a topic copy and applies it on confirmation.
+// On a protected target cluster the topic name must be typed.
+func (app *App) ConfirmCopyTopic(plan *manifest.Plan, from, to string, done func()) {
+	topic := plan.Changes[0].Topic
+	message := fmt.Sprintf(
+		"Copy topic [red::b]%s[-::-] from %s to cluster [red::b]%s[-::-]. Confirm?\n\n%s",
+		tview.Escape(topic),
+		from,
+		to,
+		tview.Escape(strings.TrimSuffix(plan.String(), "\n")),
+	)
+	app.confirmDestructiveOn(app.Clusters[to], CopyTopic, " Confirm Copy ", message, topic, func() {
+		app.CopyTopicResultHandler(plan, to, done)
+	})
+}
+
+// CopyTopicResultHandler applies the plan of a topic copy and calls done once applied.
+func (app *App) CopyTopicResultHandler(plan *manifest.Plan, to string, done func()) {
+	app.WithKafkaClient(to, func(target *client.Client) {
+		app.applyTopicCopy(plan, to, target, done)
+	})
+}
+
+func (app *App) applyTopicCopy(plan *manifest.Plan, to string, target *client.Client, done func()) {
+	topic := plan.Changes[0].Topic
+	SendStatusInfinite(fmt.Sprintf("copying topic %s to %s", topic, to))
+
+	go func() {
+		err := manifest.Apply(target, plan, func(manifest.Change, error) {})
+		if err != nil {
+			log.Error().Err(err).Msg("failed to copy topic")
+			SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to copy topic %s: %s", topic, err.Error()))
+			return
+		}
+		app.QueueUpdateDraw(done)
+	}()
+}
+
+// topicLayout formats the partitions and replication factor of a topic.
+func topicLayout(t *client.TopicSnapshot) string {
+	if t == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%d partitions, RF %d", len(t.Partitions), t.ReplicationFactor)
+}
+
+func diffSummary(d client.TopicDiff) string {
+	var parts []string
+	if d.PartitionsDiffer() {
+		parts = append(parts, "partitions")
+	}
+	if d.ReplicationFactorDiffers() {
+		parts = append(parts, "replication factor")
+	}
+	if len(d.Config) > 0 {
+		parts = append(parts, strconv.Itoa(len(d.Config))+" configs")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// configDiffText lists the overridden configs of a topic that differ between the clusters.
+func configDiffText(left, right string, d client.TopicDiff) string {
+	var entries []client.ConfigDiff
+	switch {
+	case d.Kind == client.Mismatch:
+		entries = d.Config
+	case d.Left != nil:
+		for name, value := range d.Left.Config {
+			entries = append(entries, client.ConfigDiff{Name: name, Left: value})
+		}
+	case d.Right != nil:
+		for name, value := range d.Right.Config {
+			entries = append(entries, client.ConfigDiff{Name: name, Right: value})
+		}
+	}
+	if len(entries) == 0 {
+		return "[gray]no overridden configs differ"
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+
+	var sb strings.Builder
+	for _, e := range entries {
+		sb.WriteString(fmt.Sprintf(
+			"[::b]%s[::-]\n  %s: %s\n  %s: %s\n",
+			tview.Escape(e.Name),
+			left, configValue(e.Left),
+			right, configValue(e.Right),
+		))
+	}
+	return sb.String()
+}
+
+func configValue(value string) string {
+	if value == "" {
+		return "[gray]default[-]"
+	}
+	return tview.Escape(value)
+}
@@ -39,6 +39,10 @@ func (app *App) RunClusterEventHandler(ctx context.Context, in chan Event) {
 						app.AddToPagesRegistry(Clusters, ct, ClustersPageMenu, false)
 					})
 				case GetClusterEventType:
+					if !app.isClusterSelected(app.Selected) {
+						SendStatusWithDefaultTTL("[red]to perform operation, select cluster")
+						continue
+					}
 					pageName := util.BuildPageKey(app.Selected.Cluster.Name, "info")
 					force := event.Payload.Force
 					_, found := app.Cache.Get(pageName)
@@ -54,7 +58,11 @@ func (app *App) RunClusterEventHandler(ctx context.Context, in chan Event) {
 }
 
 func (app *App) Cluster() {
-	c := app.KafkaClients[app.Selected.Cluster.Name]
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
 	rCh := make(chan *client.ClusterResult)
 	errorCh := make(chan error)
 	SendStatusInfinite("getting cluster description")
@@ -78,6 +86,10 @@ func (app *App) Cluster() {
 								Payload{nil, true},
 							)
 						}
+
+						if event.Key() == tcell.KeyRune && event.Rune() == 'S' {
+							app.ExportSnapshot()
+						}
 						return event
 					})
 
@@ -125,12 +137,54 @@ func (app *App) NewClustersTable() *tview.Table {
 	for _, cluster := range app.Config.Cinnamon.Clusters {
 		table.
 			SetCell(row, 0, tview.NewTableCell(cluster.Name)).
-			SetCell(row, 1, tview.NewTableCell(cluster.Properties["bootstrap.servers"]))
+			SetCell(row, 1, tview.NewTableCell(cluster.Properties["bootstrap.servers"])).
+			SetCell(row, 2, tview.NewTableCell(app.connectionText(cluster.Name)))
 		row++
 	}
 	return table
 }
 
+// connectionText formats the connection state of a cluster, "-" when no client
+// was created for it yet.
+func (app *App) connectionText(name string) string {
+	c, ok := app.getKafkaClient(name)
+	if !ok {
+		return "-"
+	}
+	return connectionText(c.Connection())
+}
+
+// ConnectionChanged reports a change of the connection state of a cluster and
+// updates the clusters page and the header.
+func (app *App) ConnectionChanged(name string, previous client.ConnectionState, conn client.Connection) {
+	switch {
+	case conn.State == client.Unreachable || conn.State == client.Degraded:
+		log.Warn().Err(conn.Err).Str("cluster", name).Msgf("cluster is %s", conn.State)
+		SendStatusWithDefaultTTL(fmt.Sprintf(
+			"[red]cluster '%s' is %s: %s",
+			name,
+			conn.State,
+			conn.Err.Error(),
+		))
+	case conn.State == client.Healthy && previous != client.Connecting:
+		log.Info().Str("cluster", name).Msg("cluster is healthy again")
+		SendStatusWithDefaultTTL(fmt.Sprintf("cluster '%s' is healthy again", name))
+	}
+
+	app.QueueUpdateDraw(func() {
+		if table, ok := app.Layout.PagesRegistry.UI.Pages.GetPage(Clusters).(*tview.Table); ok {
+			for row := range table.GetRowCount() {
+				if table.GetCell(row, 0).Text == name {
+					table.SetCell(row, 2, tview.NewTableCell(connectionText(conn)))
+				}
+			}
+		}
+		if app.isClusterSelected(app.Selected) && app.Selected.Cluster.Name == name {
+			app.Layout.SetConnection(conn)
+		}
+	})
+}
+
 func (app *App) ClustersTableInputHandler(ct *tview.Table) {
 	ct.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		row, _ := ct.GetSelection()
@@ -138,8 +192,10 @@ func (app *App) ClustersTableInputHandler(ct *tview.Table) {
 		cluster := app.Clusters[clusterName]
 
 		if event.Key() == tcell.KeyEnter {
-			app.SelectCluster(cluster, true)
-			ClearStatus()
+			app.SelectCluster(cluster, true, func() {
+				ct.SetCell(row, 2, tview.NewTableCell(app.connectionText(clusterName)))
+				ClearStatus()
+			})
 		}
 
 		if event.Key() == tcell.KeyRune && event.Rune() == 'd' {
@@ -150,6 +206,18 @@ func (app *App) ClustersTableInputHandler(ct *tview.Table) {
 			Publish(ClustersChannel, GetClusterEventType, Payload{Force: true})
 		}
 
+		if event.Key() == tcell.KeyRune && event.Rune() == 'S' {
+			if !app.isClusterSelected(app.Selected) || app.Selected.Cluster.Name != clusterName {
+				SendStatusWithDefaultTTL("[red]to perform operation, select cluster")
+				return event
+			}
+			app.ExportSnapshot()
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'C' {
+			app.CompareClusters()
+		}
+
 		return event
 	})
 }
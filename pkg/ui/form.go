@@ -0,0 +1,188 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/uraniumdawn/cinnamon/pkg/config"
+	"github.com/uraniumdawn/cinnamon/pkg/util"
+)
+
+// showForm shows a modal with labeled inputs. Enter focuses the selected input,
+// 's' calls submit and closes the modal when it returns true.
+func (app *App) showForm(
+	name, title string,
+	labels []string,
+	inputs []tview.Primitive,
+	submit func() bool,
+) {
+	selection := tview.NewTable()
+	for i, label := range labels {
+		selection.SetCell(i, 0, tview.NewTableCell(label).SetAlign(tview.AlignRight))
+	}
+	selection.SetSelectable(true, false)
+	selection.SetBorderPadding(0, 0, 1, 0)
+	selection.SetSelectedStyle(
+		tcell.StyleDefault.Foreground(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.FgColor),
+		).Background(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.BgColor),
+		),
+	)
+
+	toSelection := func(key tcell.Key) {
+		app.SetFocus(selection)
+		app.Layout.Menu.SetMenu(FormPageMenu)
+	}
+	fields := tview.NewFlex().SetDirection(tview.FlexRow)
+	for _, input := range inputs {
+		switch i := input.(type) {
+		case *tview.InputField:
+			i.SetDoneFunc(toSelection)
+		case *tview.DropDown:
+			i.SetDoneFunc(toSelection)
+			i.SetSelectedFunc(func(_ string, _ int) { toSelection(tcell.KeyEnter) })
+		}
+		fields.AddItem(input, 1, 0, false)
+	}
+	fields.AddItem(tview.NewBox(), 0, 1, false)
+
+	f := tview.NewFlex()
+	f.SetDirection(tview.FlexColumn)
+	f.AddItem(selection, 20, 0, true)
+	f.AddItem(tview.NewBox(), 3, 0, false)
+	f.AddItem(fields, 50, 0, false).
+		AddItem(tview.NewBox(), 0, 1, false)
+
+	selection.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := selection.GetSelection()
+
+		if event.Key() == tcell.KeyEnter && row < len(inputs) {
+			app.SetFocus(inputs[row])
+			app.Layout.Menu.SetMenu(FormInputMenu)
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 's' {
+			if submit() {
+				app.HideModalPage(name)
+			}
+		}
+
+		if event.Key() == tcell.KeyEsc {
+			app.HideModalPage(name)
+		}
+
+		return event
+	})
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(f, 0, 1, true)
+	flex.SetTitle(title)
+	flex.SetBorder(true)
+
+	modal := util.NewTopicModal(flex)
+	app.Layout.PagesRegistry.UI.Pages.AddPage(name, modal, true, false)
+	app.ShowModalPage(name)
+}
+
+// confirmDestructive asks for confirmation before a destructive action. 's' confirms,
+// unless the selected cluster is protected, then the resource name must be typed.
+func (app *App) confirmDestructive(page, title, message, resource string, confirm func()) {
+	app.confirmDestructiveOn(app.Selected.Cluster, page, title, message, resource, confirm)
+}
+
+// confirmDestructiveOn is confirmDestructive for an action on the given cluster,
+// which need not be the selected one. A message of several lines is left aligned.
+func (app *App) confirmDestructiveOn(
+	cluster *config.ClusterConfig,
+	page, title, message, resource string,
+	confirm func(),
+) {
+	lines := strings.Count(message, "\n") + 1
+	messageText := tview.NewTextView().
+		SetText(message).
+		SetTextAlign(tview.AlignCenter).
+		SetDynamicColors(true)
+	if lines > 1 {
+		messageText.SetTextAlign(tview.AlignLeft)
+	}
+
+	if !cluster.Protected {
+		messageText.SetBorder(true).
+			SetTitle(title).
+			SetBorderPadding(0, 0, 1, 1)
+
+		messageText.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if event.Key() == tcell.KeyRune && event.Rune() == 's' {
+				app.HideModalPage(page)
+				confirm()
+			}
+
+			if event.Key() == tcell.KeyEsc {
+				app.HideModalPage(page)
+			}
+
+			return event
+		})
+
+		app.Layout.PagesRegistry.PageMenuMap[page] = DeleteTopicPageMenu
+		modal := util.NewConfirmationModal(messageText)
+		if lines > 1 {
+			modal = util.NewResourceModal(messageText, lines+2)
+		}
+		app.Layout.PagesRegistry.UI.Pages.AddPage(page, modal, true, false)
+		app.ShowModalPage(page)
+		return
+	}
+
+	input := tview.NewInputField().
+		SetLabel(fmt.Sprintf("Type [::b]%s[::-] to confirm: ", tview.Escape(resource))).
+		SetFieldBackgroundColor(tcell.GetColor(app.Colors.Cinnamon.Label.BgColor))
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			if input.GetText() != resource {
+				SendStatusWithDefaultTTL("[red]typed name does not match")
+				return
+			}
+			app.HideModalPage(page)
+			confirm()
+		case tcell.KeyEsc:
+			app.HideModalPage(page)
+		}
+	})
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(messageText, lines, 0, false).
+		AddItem(tview.NewBox(), 1, 0, false).
+		AddItem(input, 1, 0, true)
+	flex.SetBorder(true).
+		SetTitle(title).
+		SetBorderPadding(0, 0, 1, 1)
+
+	app.Layout.PagesRegistry.PageMenuMap[page] = TypedConfirmPageMenu
+	modal := util.NewResourceModal(flex, lines+4)
+	app.Layout.PagesRegistry.UI.Pages.AddPage(page, modal, true, false)
+	app.ShowModalPage(page)
+}
+
+func (app *App) newEnumDropDown(width, n int, label func(i int) string) *tview.DropDown {
+	options := make([]string, n)
+	for i := range options {
+		options[i] = label(i)
+	}
+	return tview.NewDropDown().
+		SetOptions(options, nil).
+		SetCurrentOption(0).
+		SetFieldWidth(width).
+		SetFieldBackgroundColor(tcell.GetColor(app.Colors.Cinnamon.Label.BgColor))
+}
@@ -0,0 +1,211 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+	"github.com/uraniumdawn/cinnamon/pkg/util"
+)
+
+// GetHealthEventType is the event type for checking the health of the cluster.
+const GetHealthEventType EventType = "nodes:health"
+
+// Health checks the partitions of the selected cluster and displays the unhealthy
+// ones grouped by broker.
+func (app *App) Health() {
+	resultCh := make(chan *client.ClusterHealth)
+	errorCh := make(chan error)
+
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	SendStatusInfinite("checking cluster health")
+	c.ClusterHealth(resultCh, errorCh)
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
+
+	go func() {
+		for {
+			select {
+			case health := <-resultCh:
+				app.QueueUpdateDraw(func() {
+					table := app.NewHealthTable(health)
+					app.AddToPagesRegistry(
+						util.BuildPageKey(app.Selected.Cluster.Name, Health),
+						table,
+						HealthPageMenu, false,
+					)
+					ClearStatus()
+				})
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to check cluster health")
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to check cluster health: %s", err.Error()))
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while checking cluster health")
+				SendStatusWithDefaultTTL("[red]timeout while checking cluster health")
+				return
+			}
+		}
+	}()
+}
+
+// NewHealthTable creates a table of unhealthy partitions grouped by broker. Brokers
+// with the most affected partitions come first, healthy brokers last.
+func (app *App) NewHealthTable(health *client.ClusterHealth) *tview.Table {
+	table := tview.NewTable()
+	table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetBorderPadding(0, 0, 1, 0)
+	table.SetSelectedStyle(
+		tcell.StyleDefault.Foreground(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.FgColor),
+		).Background(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.BgColor),
+		),
+	)
+	table.SetTitle(util.BuildTitle(
+		Health,
+		fmt.Sprintf("%d unhealthy of %d partitions", len(health.Partitions), health.Total),
+		fmt.Sprintf("%d topics", health.Topics),
+	))
+
+	headers := []string{"Broker", "Topic", "Partition", "Issues", "Leader", "Replicas", "ISR", "Min ISR"}
+	for i, h := range headers {
+		table.SetCell(0, i, tview.NewTableCell(h).
+			SetTextColor(tcell.GetColor(app.Colors.Cinnamon.Label.FgColor)).
+			SetSelectable(false))
+	}
+
+	grouped := health.ByBroker()
+	brokers := make([]int, 0, len(health.Brokers))
+	hosts := make(map[int]string, len(health.Brokers))
+	for _, b := range health.Brokers {
+		brokers = append(brokers, int(b.ID))
+		hosts[int(b.ID)] = b.Host + ":" + strconv.Itoa(b.Port)
+	}
+	// Replicas may be assigned to brokers that are no longer part of the cluster.
+	for b := range grouped {
+		if _, ok := hosts[b]; !ok {
+			brokers = append(brokers, b)
+			hosts[b] = "unavailable"
+		}
+	}
+	sort.SliceStable(brokers, func(i, j int) bool {
+		if len(grouped[brokers[i]]) == len(grouped[brokers[j]]) {
+			return brokers[i] < brokers[j]
+		}
+		return len(grouped[brokers[i]]) > len(grouped[brokers[j]])
+	})
+
+	topics := make(map[int]string)
+	row := 1
+	for _, b := range brokers {
+		partitions := grouped[b]
+		summary := "healthy"
+		color := tcell.GetColor(app.Colors.Cinnamon.Placeholder)
+		if len(partitions) > 0 {
+			summary = issueSummary(partitions)
+			color = tcell.ColorRed
+		}
+		table.SetCell(row, 0, tview.NewTableCell(strconv.Itoa(b)+" "+hosts[b]).
+			SetTextColor(tcell.GetColor(app.Colors.Cinnamon.Title)).
+			SetSelectable(false))
+		table.SetCell(row, 1, tview.NewTableCell(summary).
+			SetTextColor(color).
+			SetSelectable(false))
+		row++
+
+		for _, p := range partitions {
+			issues := make([]string, len(p.Issues))
+			for i, issue := range p.Issues {
+				issues[i] = string(issue)
+			}
+			leader := "none"
+			if p.Leader != client.NoLeader {
+				leader = strconv.Itoa(p.Leader)
+			}
+			minISR := "-"
+			if p.MinISR > 0 {
+				minISR = strconv.Itoa(p.MinISR)
+			}
+
+			table.SetCell(row, 0, tview.NewTableCell(""))
+			table.SetCell(row, 1, tview.NewTableCell(p.Topic))
+			table.SetCell(row, 2, tview.NewTableCell(strconv.Itoa(int(p.Partition))))
+			table.SetCell(row, 3, tview.NewTableCell(strings.Join(issues, ", ")).SetTextColor(tcell.ColorRed))
+			table.SetCell(row, 4, tview.NewTableCell(leader))
+			table.SetCell(row, 5, tview.NewTableCell(joinInts(p.Replicas)))
+			table.SetCell(row, 6, tview.NewTableCell(joinInts(p.ISR)))
+			table.SetCell(row, 7, tview.NewTableCell(minISR).SetExpansion(1))
+			topics[row] = p.Topic
+			row++
+		}
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlU {
+			Publish(NodesChannel, GetHealthEventType, Payload{nil, true})
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'd' {
+			r, _ := table.GetSelection()
+			if topic, ok := topics[r]; ok {
+				Publish(TopicsChannel, GetTopicEventType, Payload{topic, false})
+			}
+		}
+
+		return event
+	})
+
+	return table
+}
+
+// issueSummary counts the partitions per issue, e.g. "2 under-replicated, 1 offline".
+func issueSummary(partitions []client.PartitionHealth) string {
+	order := []client.HealthIssue{
+		client.IssueOffline,
+		client.IssueUnderReplicated,
+		client.IssueAtMinISR,
+		client.IssueNonPreferredLeader,
+	}
+	counts := make(map[client.HealthIssue]int)
+	for _, p := range partitions {
+		for _, issue := range p.Issues {
+			counts[issue]++
+		}
+	}
+
+	var parts []string
+	for _, issue := range order {
+		if counts[issue] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[issue], issue))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func joinInts(values []int) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = strconv.Itoa(v)
+	}
+	return strings.Join(s, ",")
+}
@@ -5,6 +5,8 @@
 package ui
 
 import (
+	"fmt"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
@@ -80,8 +82,17 @@ func (app *App) OpenPagesKeyHandler(table *tview.Table) {
 
 func (app *App) MainOperationKeyHandler() {
 	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if app.Layout.Menu.ReadOnly && !app.IsSearchInFocus() && !app.IsInputInFocus() &&
+			app.Layout.Menu.IsMutating(keyLabel(event)) {
+			SendStatusWithDefaultTTL(fmt.Sprintf(
+				"[red]cluster '%s' is read-only",
+				app.Selected.Cluster.Name,
+			))
+			return nil
+		}
+
 		if event.Key() == tcell.KeyRune && event.Rune() == ':' {
-			if !app.IsSearchInFocus() {
+			if !app.IsSearchInFocus() && !app.IsInputInFocus() {
 				app.ShowModalPage(Resources)
 			}
 		}
@@ -133,3 +144,18 @@ func (app *App) SearchKeyHandler(input *tview.InputField) {
 		return event
 	})
 }
+
+// keyLabel formats a key event the way menu bindings label their keys, e.g. <c>,
+// <Ctrl+d> or <Enter>.
+func keyLabel(event *tcell.EventKey) string {
+	switch key := event.Key(); {
+	case key == tcell.KeyRune:
+		return fmt.Sprintf("<%c>", event.Rune())
+	case key == tcell.KeyEnter:
+		return "<Enter>"
+	case key >= tcell.KeyCtrlA && key <= tcell.KeyCtrlZ:
+		return fmt.Sprintf("<Ctrl+%c>", 'a'+rune(key-tcell.KeyCtrlA))
+	default:
+		return event.Name()
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+	"github.com/uraniumdawn/cinnamon/pkg/util"
+)
+
+// GetLagOverviewEventType is the event type for fetching the lag of all consumer groups.
+const GetLagOverviewEventType EventType = "cgroups:lag"
+
+// lagOrder is a sort order of the lag overview.
+type lagOrder struct {
+	name string
+	less func(a, b client.GroupLag) bool
+}
+
+var lagOrders = []lagOrder{
+	{"total lag", func(a, b client.GroupLag) bool { return a.TotalLag > b.TotalLag }},
+	{"max lag", func(a, b client.GroupLag) bool { return a.MaxLag > b.MaxLag }},
+	{"group", func(a, b client.GroupLag) bool { return a.Group < b.Group }},
+}
+
+// LagOverview fetches the lag of all consumer groups and displays them sorted by total lag.
+func (app *App) LagOverview() {
+	resultCh := make(chan []client.GroupLag)
+	errorCh := make(chan error)
+
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	SendStatusInfinite("getting consumer lag")
+	c.LagOverview(resultCh, errorCh)
+	// Groups are fetched by a worker pool within the client timeout, leave room
+	// for the stragglers to be reported instead of timing out here.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*app.Config.GetAPICallTimeout())
+
+	go func() {
+		for {
+			select {
+			case lags := <-resultCh:
+				app.QueueUpdateDraw(func() {
+					table := app.NewLagOverviewTable(lags)
+					app.AddToPagesRegistry(
+						util.BuildPageKey(app.Selected.Cluster.Name, LagOverview),
+						table,
+						LagOverviewPageMenu, false,
+					)
+					failed := 0
+					for _, l := range lags {
+						if l.Error != nil {
+							failed++
+						}
+					}
+					if failed > 0 {
+						SendStatusWithDefaultTTL(
+							fmt.Sprintf("[red]failed to get lag of %d groups", failed),
+						)
+					} else {
+						ClearStatus()
+					}
+				})
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to get consumer lag")
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to get consumer lag: %s", err.Error()))
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while getting consumer lag")
+				SendStatusWithDefaultTTL("[red]timeout while getting consumer lag")
+				return
+			}
+		}
+	}()
+}
+
+// NewLagOverviewTable creates a table displaying the lag of consumer groups.
+// 'o' cycles the sort order between total lag, max lag and group name.
+func (app *App) NewLagOverviewTable(lags []client.GroupLag) *tview.Table {
+	table := tview.NewTable()
+	table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetBorderPadding(0, 0, 1, 0)
+	table.SetSelectedStyle(
+		tcell.StyleDefault.Foreground(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.FgColor),
+		).Background(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.BgColor),
+		),
+	)
+
+	order := 0
+	render := func() {
+		sort.SliceStable(lags, func(i, j int) bool {
+			return lagOrders[order].less(lags[i], lags[j])
+		})
+		table.Clear()
+		table.SetTitle(util.BuildTitle(
+			LagOverview,
+			"by "+lagOrders[order].name,
+			"["+strconv.Itoa(len(lags))+"]",
+		))
+
+		headers := []string{"Group", "State", "Members", "Topics", "Total Lag", "Max Lag"}
+		for i, h := range headers {
+			cell := tview.NewTableCell(h).
+				SetTextColor(tcell.GetColor(app.Colors.Cinnamon.Label.FgColor)).
+				SetSelectable(false)
+			if i >= 2 {
+				cell.SetAlign(tview.AlignRight)
+			}
+			table.SetCell(0, i, cell)
+		}
+
+		for i, l := range lags {
+			row := i + 1
+			table.SetCell(row, 0, tview.NewTableCell(l.Group).SetExpansion(1))
+			table.SetCell(row, 1, tview.NewTableCell(l.State.String()))
+			table.SetCell(row, 2, tview.NewTableCell(strconv.Itoa(l.Members)).SetAlign(tview.AlignRight))
+			if l.Error != nil {
+				table.SetCell(row, 3, tview.NewTableCell(l.Error.Error()).
+					SetTextColor(tcell.ColorRed))
+				continue
+			}
+			table.SetCell(row, 3, tview.NewTableCell(strconv.Itoa(l.Topics)).SetAlign(tview.AlignRight))
+			table.SetCell(row, 4, tview.NewTableCell(strconv.FormatInt(l.TotalLag, 10)).
+				SetAlign(tview.AlignRight))
+			table.SetCell(row, 5, tview.NewTableCell(strconv.FormatInt(l.MaxLag, 10)).
+				SetAlign(tview.AlignRight))
+		}
+	}
+	render()
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlU {
+			Publish(CgroupsChannel, GetLagOverviewEventType, Payload{nil, true})
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'o' {
+			order = (order + 1) % len(lagOrders)
+			render()
+			table.Select(1, 0)
+			return nil
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'd' {
+			row, _ := table.GetSelection()
+			if row >= 1 && row <= len(lags) {
+				Publish(CgroupsChannel, GetCgroupEventType, Payload{lags[row-1].Group, false})
+			}
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'w' {
+			row, _ := table.GetSelection()
+			if row >= 1 && row <= len(lags) {
+				app.WatchConsumerGroup(lags[row-1].Group)
+			}
+			return nil
+		}
+
+		return event
+	})
+
+	return table
+}
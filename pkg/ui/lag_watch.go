@@ -0,0 +1,274 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+	"github.com/uraniumdawn/cinnamon/pkg/util"
+)
+
+const sparklineWidth = 30
+
+// lagSample holds the offsets of a consumer group at a point in time.
+type lagSample struct {
+	at      time.Time
+	current map[client.TopicPartition]kafka.Offset
+	end     map[client.TopicPartition]kafka.Offset
+}
+
+// lagHistory is a rolling window of lag samples of a consumer group.
+type lagHistory struct {
+	size    int
+	samples []lagSample
+	mx      sync.Mutex
+}
+
+func (h *lagHistory) add(s lagSample) {
+	h.mx.Lock()
+	defer h.mx.Unlock()
+	h.samples = append(h.samples, s)
+	if len(h.samples) > h.size {
+		h.samples = h.samples[len(h.samples)-h.size:]
+	}
+}
+
+// lagSeries contains the lag and rate series of a partition or of the whole group.
+type lagSeries struct {
+	lag     []float64
+	consume []float64
+	produce []float64
+	// consumeRate and produceRate are averaged over the whole window.
+	consumeRate float64
+	produceRate float64
+}
+
+// series derives the series of the partitions selected by include, summed up.
+func (h *lagHistory) series(include func(tp client.TopicPartition) bool) lagSeries {
+	var s lagSeries
+	sum := func(offsets map[client.TopicPartition]kafka.Offset, committed bool) float64 {
+		total := 0.0
+		for tp, o := range offsets {
+			if include(tp) && (!committed || o >= 0) {
+				total += float64(o)
+			}
+		}
+		return total
+	}
+
+	for i, sample := range h.samples {
+		lag := 0.0
+		for tp, current := range sample.current {
+			if end, ok := sample.end[tp]; ok && include(tp) && current >= 0 && end > current {
+				lag += float64(end - current)
+			}
+		}
+		s.lag = append(s.lag, lag)
+
+		if i == 0 {
+			continue
+		}
+		prev := h.samples[i-1]
+		seconds := sample.at.Sub(prev.at).Seconds()
+		s.consume = append(s.consume,
+			(sum(sample.current, true)-sum(prev.current, true))/seconds)
+		s.produce = append(s.produce,
+			(sum(sample.end, false)-sum(prev.end, false))/seconds)
+	}
+
+	if n := len(h.samples); n > 1 {
+		first, last := h.samples[0], h.samples[n-1]
+		seconds := last.at.Sub(first.at).Seconds()
+		s.consumeRate = (sum(last.current, true) - sum(first.current, true)) / seconds
+		s.produceRate = (sum(last.end, false) - sum(first.end, false)) / seconds
+	}
+	return s
+}
+
+// catchUp estimates the time until the lag is consumed at the current rates.
+func (s lagSeries) catchUp() string {
+	if len(s.lag) == 0 || len(s.consume) == 0 {
+		return "n/a"
+	}
+	lag := s.lag[len(s.lag)-1]
+	if lag <= 0 {
+		return "caught up"
+	}
+	net := s.consumeRate - s.produceRate
+	if net <= 0 {
+		return "falling behind"
+	}
+	return (time.Duration(lag/net) * time.Second).String()
+}
+
+// WatchConsumerGroup opens a page that describes a consumer group on the monitor
+// interval and renders the lag trend, consume and produce rates as sparklines.
+// Polling stops when the page is removed or replaced.
+func (app *App) WatchConsumerGroup(name string) {
+	pageName := util.BuildPageKey(app.Selected.Cluster.Name, ConsumerGroup, name, LagWatch)
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	interval := app.Config.GetMonitorInterval()
+	history := &lagHistory{size: app.Config.GetMonitorHistory()}
+
+	view := app.NewDescription(util.BuildTitle(LagWatch, name))
+	var paused bool
+	var lastErr error
+	render := func() {
+		view.SetText(renderLagWatch(name, interval, history, paused, lastErr))
+	}
+	render()
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() == 'p' {
+			paused = !paused
+			render()
+			return nil
+		}
+		return event
+	})
+	app.AddToPagesRegistry(pageName, view, LagWatchPageMenu, false)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			// Buffered, so a late result of a failed describe does not block the client.
+			resultCh := make(chan *client.DescribeConsumerGroupResult, 1)
+			errorCh := make(chan error, 3)
+			c.DescribeConsumerGroup(name, resultCh, errorCh)
+
+			var sample *lagSample
+			var err error
+			select {
+			case result := <-resultCh:
+				current, end := result.Offsets()
+				sample = &lagSample{at: time.Now(), current: current, end: end}
+			case err = <-errorCh:
+				log.Error().Err(err).Str("group", name).Msg("failed to sample consumer group")
+			case <-time.After(app.Config.GetAPICallTimeout()):
+				err = fmt.Errorf("timeout while describing consumer group")
+			}
+
+			if shown, _ := app.pageState(pageName, view); !shown {
+				log.Debug().Str("group", name).Msg("stopped watching consumer group")
+				return
+			}
+			app.QueueUpdateDraw(func() {
+				if !paused {
+					if sample != nil {
+						history.add(*sample)
+					}
+					lastErr = err
+					render()
+				}
+			})
+
+			<-ticker.C
+		}
+	}()
+}
+
+func renderLagWatch(
+	name string,
+	interval time.Duration,
+	history *lagHistory,
+	paused bool,
+	lastErr error,
+) string {
+	history.mx.Lock()
+	defer history.mx.Unlock()
+
+	var sb strings.Builder
+	state := "watching"
+	if paused {
+		state = "paused"
+	}
+	sb.WriteString(fmt.Sprintf(
+		"Group: %s, %s every %s, %d samples\n",
+		tview.Escape(name), state, interval, len(history.samples),
+	))
+	if lastErr != nil {
+		sb.WriteString(fmt.Sprintf("[red]Last sample failed: %s[-]\n", tview.Escape(lastErr.Error())))
+	}
+	if len(history.samples) == 0 {
+		return sb.String()
+	}
+
+	total := history.series(func(client.TopicPartition) bool { return true })
+	sb.WriteString("\n")
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Lag\t%s\t%s\n", util.Sparkline(total.lag, sparklineWidth), formatCount(lastOf(total.lag)))
+	fmt.Fprintf(w, "Consume rate\t%s\t%s/s\n",
+		util.Sparkline(total.consume, sparklineWidth), formatRate(lastOf(total.consume)))
+	fmt.Fprintf(w, "Produce rate\t%s\t%s/s\n",
+		util.Sparkline(total.produce, sparklineWidth), formatRate(lastOf(total.produce)))
+	fmt.Fprintf(w, "Time to catch up\t%s\t\n", total.catchUp())
+	w.Flush()
+
+	last := history.samples[len(history.samples)-1]
+	var partitions []client.TopicPartition
+	for tp, offset := range last.current {
+		if offset >= 0 {
+			partitions = append(partitions, tp)
+		}
+	}
+	sort.Slice(partitions, func(i, j int) bool {
+		if partitions[i].Topic == partitions[j].Topic {
+			return partitions[i].Partition < partitions[j].Partition
+		}
+		return partitions[i].Topic < partitions[j].Topic
+	})
+
+	sb.WriteString("\n")
+	w = tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Topic\tPartition\tLag\tTrend\tConsume/s\tProduce/s\tCatch up")
+	for _, tp := range partitions {
+		s := history.series(func(other client.TopicPartition) bool { return other == tp })
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			tview.Escape(tp.Topic),
+			tp.Partition,
+			formatCount(lastOf(s.lag)),
+			util.Sparkline(s.lag, sparklineWidth),
+			formatRate(s.consumeRate),
+			formatRate(s.produceRate),
+			s.catchUp(),
+		)
+	}
+	w.Flush()
+
+	return sb.String()
+}
+
+func lastOf(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	return values[len(values)-1]
+}
+
+func formatCount(v float64) string {
+	return strconv.FormatInt(int64(v), 10)
+}
+
+func formatRate(v float64) string {
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 
+	"github.com/uraniumdawn/cinnamon/pkg/client"
 	"github.com/uraniumdawn/cinnamon/pkg/config"
 )
 
@@ -68,7 +69,7 @@ func NewLayout(registry *PagesRegistry, colors *config.ColorConfig) *Layout {
 		SetBackgroundColor(tcell.GetColor(colors.Cinnamon.Cluster.BgColor)).
 		SetExpansion(1))
 
-	cluster.SetCell(1, 0, tview.NewTableCell("Schema Registry:").
+	cluster.SetCell(1, 0, tview.NewTableCell("Connection:").
 		SetTextColor(tcell.GetColor(colors.Cinnamon.Label.FgColor)).
 		SetBackgroundColor(tcell.GetColor(colors.Cinnamon.Cluster.BgColor)).
 		SetExpansion(0))
@@ -77,6 +78,15 @@ func NewLayout(registry *PagesRegistry, colors *config.ColorConfig) *Layout {
 		SetBackgroundColor(tcell.GetColor(colors.Cinnamon.Cluster.BgColor)).
 		SetExpansion(1))
 
+	cluster.SetCell(2, 0, tview.NewTableCell("Schema Registry:").
+		SetTextColor(tcell.GetColor(colors.Cinnamon.Label.FgColor)).
+		SetBackgroundColor(tcell.GetColor(colors.Cinnamon.Cluster.BgColor)).
+		SetExpansion(0))
+	cluster.SetCell(2, 1, tview.NewTableCell("").
+		SetTextColor(tcell.GetColor(colors.Cinnamon.Cluster.FgColor)).
+		SetBackgroundColor(tcell.GetColor(colors.Cinnamon.Cluster.BgColor)).
+		SetExpansion(1))
+
 	menu := NewMenu(colors)
 	header := tview.NewFlex()
 	header.SetDirection(tview.FlexColumn)
@@ -157,11 +167,36 @@ func (l *Layout) SetSelected(cluster *config.ClusterConfig, sr *config.SchemaReg
 
 	if cluster != nil {
 		clusterName = cluster.Name
+		switch {
+		case cluster.ReadOnly:
+			clusterName += " (read-only)"
+		case cluster.Protected:
+			clusterName += " (protected)"
+		}
 	}
 	if sr != nil {
 		srName = sr.Name
 	}
 
 	l.Cluster.GetCell(0, 1).SetText(clusterName)
-	l.Cluster.GetCell(1, 1).SetText(srName)
+	l.Cluster.GetCell(2, 1).SetText(srName)
+}
+
+// SetConnection shows the connection state of the selected cluster.
+func (l *Layout) SetConnection(conn client.Connection) {
+	l.Cluster.GetCell(1, 1).SetText(connectionText(conn))
+}
+
+// connectionText formats a connection state with its color.
+func connectionText(conn client.Connection) string {
+	switch conn.State {
+	case client.Healthy:
+		return "[green]" + string(conn.State)
+	case client.Degraded:
+		return "[orange]" + string(conn.State)
+	case client.Unreachable:
+		return "[red]" + string(conn.State)
+	default:
+		return "[yellow]" + string(conn.State)
+	}
 }
@@ -0,0 +1,279 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+	"github.com/uraniumdawn/cinnamon/pkg/util"
+)
+
+const (
+	electionPreferred = "preferred"
+	electionUnclean   = "unclean"
+)
+
+// ElectLeaders shows the form to run a leader election for partitions of a topic.
+func (app *App) ElectLeaders(topicName string) {
+	width := 40
+
+	electionType := tview.NewDropDown().
+		SetOptions([]string{electionPreferred, electionUnclean}, nil).
+		SetCurrentOption(0).
+		SetFieldWidth(width).
+		SetFieldBackgroundColor(tcell.GetColor(app.Colors.Cinnamon.Label.BgColor))
+
+	partitions := app.NewInputField("Comma separated, empty for all", width)
+
+	selection := tview.NewTable()
+	selection.SetCell(0, 0, tview.NewTableCell("Election:").SetAlign(tview.AlignRight))
+	selection.SetCell(1, 0, tview.NewTableCell("Partitions:").SetAlign(tview.AlignRight))
+	selection.SetSelectable(true, false)
+	selection.SetBorderPadding(0, 0, 1, 0)
+	selection.SetSelectedStyle(
+		tcell.StyleDefault.Foreground(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.FgColor),
+		).Background(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.BgColor),
+		),
+	)
+
+	toSelection := func(key tcell.Key) {
+		app.SetFocus(selection)
+		app.Layout.Menu.SetMenu(ElectLeadersPageMenu)
+	}
+	electionType.SetDoneFunc(toSelection)
+	electionType.SetSelectedFunc(func(_ string, _ int) { toSelection(tcell.KeyEnter) })
+	partitions.SetDoneFunc(toSelection)
+	inputs := []tview.Primitive{electionType, partitions}
+
+	f := tview.NewFlex()
+	f.SetDirection(tview.FlexColumn)
+	f.AddItem(selection, 20, 0, true)
+	f.AddItem(tview.NewBox(), 3, 0, false)
+	f.AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(electionType, 1, 0, false).
+		AddItem(partitions, 1, 0, false).
+		AddItem(tview.NewBox(), 0, 1, false), width, 0, false).
+		AddItem(tview.NewBox(), 0, 1, false)
+
+	selection.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := selection.GetSelection()
+
+		if event.Key() == tcell.KeyEnter && row < len(inputs) {
+			app.SetFocus(inputs[row])
+			app.Layout.Menu.SetMenu(ElectLeadersInputMenu)
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 's' {
+			parsed, err := parsePartitions(partitions.GetText())
+			if err != nil {
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+				return event
+			}
+
+			_, option := electionType.GetCurrentOption()
+			params := client.ElectLeadersParams{
+				Type:       kafka.ElectionTypePreferred,
+				Topic:      topicName,
+				Partitions: parsed,
+				Broker:     client.AnyBroker,
+			}
+
+			app.HideModalPage(ElectLeaders)
+			if option == electionUnclean {
+				params.Type = kafka.ElectionTypeUnclean
+				app.ConfirmUncleanElection(params)
+			} else {
+				app.ElectLeadersResultHandler(params)
+			}
+		}
+
+		if event.Key() == tcell.KeyEsc {
+			app.HideModalPage(ElectLeaders)
+		}
+
+		return event
+	})
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(f, 0, 1, true)
+	flex.SetTitle(fmt.Sprintf(" Elect Leaders: %s ", topicName))
+	flex.SetBorder(true)
+
+	modal := util.NewTopicModal(flex)
+	app.Layout.PagesRegistry.UI.Pages.AddPage(ElectLeaders, modal, true, false)
+	app.ShowModalPage(ElectLeaders)
+}
+
+// ConfirmUncleanElection asks for confirmation before an unclean leader election,
+// which may elect an out-of-sync replica and lose committed records.
+func (app *App) ConfirmUncleanElection(params client.ElectLeadersParams) {
+	message := fmt.Sprintf(
+		"Unclean election for [red::b]%s[-::-] may lose committed records. Confirm?",
+		tview.Escape(electionScope(params)),
+	)
+	resource := params.Topic
+	if resource == "" {
+		resource = app.Selected.Cluster.Name
+	}
+	title := " Confirm Unclean Election "
+	app.confirmDestructive(ConfirmUncleanElection, title, message, resource, func() {
+		app.ElectLeadersResultHandler(params)
+	})
+}
+
+// ConfirmClusterElection asks for confirmation before a preferred leader election
+// of every partition of the selected cluster, which moves leadership across brokers.
+func (app *App) ConfirmClusterElection() {
+	cluster := app.Selected.Cluster.Name
+	message := fmt.Sprintf(
+		"Preferred election for all partitions of cluster [red::b]%s[-::-]. Confirm?",
+		tview.Escape(cluster),
+	)
+	title := " Confirm Cluster Election "
+	app.confirmDestructive(ConfirmClusterElection, title, message, cluster, func() {
+		app.ElectLeadersResultHandler(client.ElectLeadersParams{
+			Type:   kafka.ElectionTypePreferred,
+			Broker: client.AnyBroker,
+		})
+	})
+}
+
+// ElectLeadersResultHandler runs a leader election and shows per-partition results.
+func (app *App) ElectLeadersResultHandler(params client.ElectLeadersParams) {
+	resultCh := make(chan *client.ElectLeadersResult)
+	errorCh := make(chan error)
+
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	SendStatusInfinite("electing leaders")
+	c.ElectLeaders(params, resultCh, errorCh)
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
+
+	go func() {
+		for {
+			select {
+			case result := <-resultCh:
+				app.QueueUpdateDraw(func() {
+					table := app.NewElectionsTable(result)
+					app.AddToPagesRegistry(
+						util.BuildPageKey(
+							app.Selected.Cluster.Name,
+							LeaderElection,
+							electionScope(params),
+						),
+						table,
+						LeaderElectionPageMenu, false,
+					)
+					if len(result.Elections) == 0 {
+						SendStatusWithDefaultTTL("all partitions are led by their preferred replica")
+					} else {
+						ClearStatus()
+					}
+				})
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to elect leaders")
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to elect leaders: %s", err.Error()))
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while electing leaders")
+				SendStatusWithDefaultTTL("[red]timeout while electing leaders")
+				return
+			}
+		}
+	}()
+}
+
+// NewElectionsTable creates a table displaying per-partition leader election results.
+func (app *App) NewElectionsTable(result *client.ElectLeadersResult) *tview.Table {
+	params := result.Params
+
+	table := tview.NewTable()
+	table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetBorderPadding(0, 0, 1, 0)
+	table.SetSelectedStyle(
+		tcell.StyleDefault.Foreground(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.FgColor),
+		).Background(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.BgColor),
+		),
+	)
+
+	election := electionPreferred
+	if params.Type == kafka.ElectionTypeUnclean {
+		election = electionUnclean
+	}
+	table.SetTitle(util.BuildTitle(
+		LeaderElection,
+		election,
+		electionScope(params),
+		"["+strconv.Itoa(len(result.Elections))+"]",
+	))
+
+	for i, h := range []string{"Topic", "Partition", "Leader", "Preferred", "Result"} {
+		table.SetCell(0, i, tview.NewTableCell(h).
+			SetTextColor(tcell.GetColor(app.Colors.Cinnamon.Label.FgColor)).
+			SetSelectable(false))
+	}
+
+	for i, e := range result.Elections {
+		outcome := tview.NewTableCell("elected")
+		var kafkaErr kafka.Error
+		switch {
+		case e.Error == nil:
+		case errors.As(e.Error, &kafkaErr) && kafkaErr.Code() == kafka.ErrElectionNotNeeded:
+			outcome.SetText("not needed")
+		default:
+			outcome.SetText(e.Error.Error()).SetTextColor(tcell.ColorRed)
+		}
+
+		row := i + 1
+		table.SetCell(row, 0, tview.NewTableCell(e.Topic))
+		table.SetCell(row, 1, tview.NewTableCell(strconv.Itoa(int(e.Partition))))
+		table.SetCell(row, 2, tview.NewTableCell(strconv.Itoa(int(e.Leader))))
+		table.SetCell(row, 3, tview.NewTableCell(strconv.Itoa(int(e.Preferred))))
+		table.SetCell(row, 4, outcome.SetExpansion(1))
+	}
+
+	return table
+}
+
+// electionScope describes the partitions of an election for titles and page keys.
+func electionScope(params client.ElectLeadersParams) string {
+	switch {
+	case params.Topic != "" && len(params.Partitions) > 0:
+		names := make([]string, len(params.Partitions))
+		for i, p := range params.Partitions {
+			names[i] = strconv.Itoa(int(p))
+		}
+		return params.Topic + ":" + strings.Join(names, ",")
+	case params.Topic != "":
+		return params.Topic
+	case params.Broker != client.AnyBroker:
+		return "broker " + strconv.Itoa(int(params.Broker))
+	default:
+		return "imbalanced"
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/manifest"
+	"github.com/uraniumdawn/cinnamon/pkg/policy"
+	"github.com/uraniumdawn/cinnamon/pkg/util"
+)
+
+// DefaultManifestPath is the manifest offered by the plan form.
+const DefaultManifestPath = "topics.yaml"
+
+// PlanManifest shows the form asking for the path of a topic manifest to plan.
+func (app *App) PlanManifest() {
+	path := app.NewInputField("", 50).SetText(DefaultManifestPath)
+
+	app.showForm(
+		PlanManifest,
+		" Plan Topic Manifest ",
+		[]string{"Manifest"},
+		[]tview.Primitive{path},
+		func() bool {
+			p := strings.TrimSpace(path.GetText())
+			if p == "" {
+				SendStatusWithDefaultTTL("[red]manifest path is required")
+				return false
+			}
+			app.TopicsPlan(p)
+			return true
+		},
+	)
+}
+
+// TopicsPlan diffs a topic manifest against the selected cluster and displays the
+// plan. The plan is applied from the page after a confirmation.
+func (app *App) TopicsPlan(path string) {
+	rules, err := app.topicPolicies()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	cluster := app.Selected.Cluster.Name
+	SendStatusInfinite("planning topic manifest")
+
+	go func() {
+		m, err := manifest.Load(path)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to load topic manifest")
+			SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to load topic manifest: %s", err.Error()))
+			return
+		}
+
+		live, err := manifest.Live(c, m)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to plan topic manifest")
+			SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to plan topic manifest: %s", err.Error()))
+			return
+		}
+
+		defaults, err := c.TopicDefaults(policy.ConfigKeys(rules))
+		if err != nil {
+			log.Error().Err(err).Msg("failed to get topic defaults")
+			SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to get topic defaults: %s", err.Error()))
+			return
+		}
+
+		plan := manifest.NewPlan(m, live, rules, defaults)
+		app.QueueUpdateDraw(func() {
+			view := app.NewTopicsPlanView(path, plan)
+			app.AddToPagesRegistry(
+				util.BuildPageKey(cluster, TopicsPlan, path),
+				view,
+				TopicsPlanPageMenu, false,
+			)
+			ClearStatus()
+		})
+	}()
+}
+
+// NewTopicsPlanView renders a plan, creates in green, updates in yellow, invalid
+// changes in red and unmanaged topics dimmed.
+func (app *App) NewTopicsPlanView(path string, plan *manifest.Plan) *tview.TextView {
+	var sb strings.Builder
+	for _, line := range strings.Split(plan.String(), "\n") {
+		color := ""
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "+"):
+			color = "[green]"
+		case strings.HasPrefix(trimmed, "~"):
+			color = "[yellow]"
+		case strings.HasPrefix(trimmed, "!"):
+			color = "[red]"
+		case strings.HasPrefix(trimmed, "?"):
+			color = fmt.Sprintf("[%s]", app.Colors.Cinnamon.Placeholder)
+		}
+		if color != "" {
+			sb.WriteString(color + tview.Escape(line) + "[-]\n")
+		} else {
+			sb.WriteString(tview.Escape(line) + "\n")
+		}
+	}
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetText(sb.String())
+	view.SetBorder(true).
+		SetBorderPadding(0, 0, 1, 1).
+		SetTitle(util.BuildTitle(TopicsPlan, path))
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlU {
+			app.TopicsPlan(path)
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 's' {
+			switch {
+			case plan.Count(manifest.Invalid) > 0:
+				SendStatusWithDefaultTTL("[red]the plan has invalid changes, fix the manifest first")
+			case !plan.HasChanges():
+				SendStatusWithDefaultTTL("no changes to apply")
+			default:
+				app.ConfirmApplyPlan(path, plan)
+				app.ShowModalPage(ConfirmApplyPlan)
+			}
+		}
+
+		return event
+	})
+
+	return view
+}
+
+// ConfirmApplyPlan asks for confirmation before applying a plan.
+func (app *App) ConfirmApplyPlan(path string, plan *manifest.Plan) {
+	messageText := tview.NewTextView().
+		SetText(fmt.Sprintf(
+			"Apply [red::b]%d[-::-] changes of %s to cluster [red::b]%s[-::-]. Confirm?",
+			plan.Count(manifest.Create)+plan.Count(manifest.UpdateConfig)+plan.Count(manifest.IncreasePartitions),
+			tview.Escape(path),
+			app.Selected.Cluster.Name,
+		)).
+		SetTextAlign(tview.AlignCenter).
+		SetDynamicColors(true)
+
+	messageText.SetBorder(true).
+		SetTitle(" Confirm Apply ").
+		SetBorderPadding(0, 0, 1, 1)
+
+	messageText.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() == 's' {
+			app.ApplyPlanResultHandler(path, plan)
+			app.HideModalPage(ConfirmApplyPlan)
+		}
+
+		if event.Key() == tcell.KeyEsc {
+			app.HideModalPage(ConfirmApplyPlan)
+		}
+
+		return event
+	})
+
+	modal := util.NewConfirmationModal(messageText)
+	app.Layout.PagesRegistry.UI.Pages.AddPage(ConfirmApplyPlan, modal, true, true)
+	app.Layout.PagesRegistry.UI.Pages.ShowPage(ConfirmApplyPlan)
+}
+
+// ApplyPlanResultHandler applies a plan, reporting every change in the status bar.
+// Once applied the manifest is planned again to show the remaining differences.
+func (app *App) ApplyPlanResultHandler(path string, plan *manifest.Plan) {
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	SendStatusInfinite("applying topic manifest")
+
+	go func() {
+		err := manifest.Apply(c, plan, func(change manifest.Change, err error) {
+			if err == nil {
+				SendStatusInfinite(fmt.Sprintf("%s: %s complete", change.Topic, change.Kind))
+			}
+		})
+		Publish(TopicsChannel, GetTopicsEventType, Payload{nil, true})
+		if err != nil {
+			log.Error().Err(err).Msg("failed to apply topic manifest")
+			SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to apply topic manifest: %s", err.Error()))
+			return
+		}
+		app.TopicsPlan(path)
+	}()
+}
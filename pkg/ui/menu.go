@@ -13,11 +13,16 @@ type Menu struct {
 	Flex    *tview.Flex
 	Map     *map[string]*[]string
 	Colors  *config.ColorConfig
+	// ReadOnly hides the bindings of mutating actions, set for read-only clusters.
+	ReadOnly bool
+	current  string
 }
 
 type Pair struct {
 	Key   string
 	Value string
+	// Mutating marks bindings that change the cluster, disabled in read-only mode.
+	Mutating bool
 }
 
 var keys = map[string]Pair{
@@ -70,16 +75,19 @@ var keys = map[string]Pair{
 		Value: "Default",
 	},
 	"create": {
-		Key:   "<c>",
-		Value: "Create Topic",
+		Key:      "<c>",
+		Value:    "Create Topic",
+		Mutating: true,
 	},
 	"delete": {
-		Key:   "<Ctrl+d>",
-		Value: "Delete Topic",
+		Key:      "<Ctrl+d>",
+		Value:    "Delete Topic",
+		Mutating: true,
 	},
 	"edit": {
-		Key:   "<e>",
-		Value: "Edit Topic",
+		Key:      "<e>",
+		Value:    "Edit Topic",
+		Mutating: true,
 	},
 	"submit": {
 		Key:   "<s>",
@@ -102,8 +110,9 @@ var keys = map[string]Pair{
 		Value: "CLI commands",
 	},
 	"execute_cli": {
-		Key:   "<e>",
-		Value: "Execute CLI command (Beta)",
+		Key:      "<e>",
+		Value:    "Execute CLI command (Beta)",
+		Mutating: true,
 	},
 	"copy_cli": {
 		Key:   "<c>",
@@ -117,6 +126,157 @@ var keys = map[string]Pair{
 		Key:   "<Ctrl+k>",
 		Value: "Kill process",
 	},
+	"messages": {
+		Key:   "<m>",
+		Value: "Browse Messages",
+	},
+	"produce": {
+		Key:      "<P>",
+		Value:    "Produce Message",
+		Mutating: true,
+	},
+	"peek": {
+		Key:   "<v>",
+		Value: "Peek Latest Messages",
+	},
+	"next_page": {
+		Key:   "<n>",
+		Value: "Next Page",
+	},
+	"prev_page": {
+		Key:   "<b>",
+		Value: "Previous Page",
+	},
+	"switch_focus": {
+		Key:   "<Tab>",
+		Value: "Switch Focus",
+	},
+	"reset_offsets": {
+		Key:      "<R>",
+		Value:    "Reset Offsets",
+		Mutating: true,
+	},
+	"apply": {
+		Key:      "<s>",
+		Value:    "Apply",
+		Mutating: true,
+	},
+	"mark": {
+		Key:   "<Space>",
+		Value: "Mark",
+	},
+	"delete_cgroups": {
+		Key:      "<Ctrl+d>",
+		Value:    "Delete Groups",
+		Mutating: true,
+	},
+	"delete_records": {
+		Key:      "<T>",
+		Value:    "Delete Records",
+		Mutating: true,
+	},
+	"elect_leaders": {
+		Key:      "<L>",
+		Value:    "Elect Leaders",
+		Mutating: true,
+	},
+	"elect_preferred": {
+		Key:      "<L>",
+		Value:    "Elect Preferred Leaders",
+		Mutating: true,
+	},
+	"filter": {
+		Key:   "<f>",
+		Value: "Filter",
+	},
+	"create_acl": {
+		Key:      "<c>",
+		Value:    "Create ACL",
+		Mutating: true,
+	},
+	"delete_acl": {
+		Key:      "<Ctrl+d>",
+		Value:    "Delete ACL",
+		Mutating: true,
+	},
+	"create_credential": {
+		Key:      "<c>",
+		Value:    "Create Credential",
+		Mutating: true,
+	},
+	"update_credential": {
+		Key:      "<Enter>",
+		Value:    "Update Credential",
+		Mutating: true,
+	},
+	"delete_credential": {
+		Key:      "<Ctrl+d>",
+		Value:    "Delete Credential",
+		Mutating: true,
+	},
+	"edit_config": {
+		Key:      "<e>",
+		Value:    "Edit Config",
+		Mutating: true,
+	},
+	"add_config": {
+		Key:      "<c>",
+		Value:    "Set Config",
+		Mutating: true,
+	},
+	"delete_override": {
+		Key:      "<Ctrl+d>",
+		Value:    "Delete Override",
+		Mutating: true,
+	},
+	"broker_defaults": {
+		Key:   "<D>",
+		Value: "Cluster Defaults",
+	},
+	"lag_overview": {
+		Key:   "<O>",
+		Value: "Lag Overview",
+	},
+	"sort": {
+		Key:   "<o>",
+		Value: "Sort",
+	},
+	"watch": {
+		Key:   "<w>",
+		Value: "Watch Lag",
+	},
+	"pause": {
+		Key:   "<p>",
+		Value: "Pause/Resume",
+	},
+	"health": {
+		Key:   "<H>",
+		Value: "Cluster Health",
+	},
+	"plan_manifest": {
+		Key:   "<A>",
+		Value: "Plan Manifest",
+	},
+	"export_snapshot": {
+		Key:   "<S>",
+		Value: "Export Snapshot",
+	},
+	"compare_clusters": {
+		Key:   "<C>",
+		Value: "Compare Clusters",
+	},
+	"copy_right": {
+		Key:   "<>>",
+		Value: "Copy To Right",
+	},
+	"copy_left": {
+		Key:   "<<>",
+		Value: "Copy To Left",
+	},
+	"policy_violations": {
+		Key:   "<V>",
+		Value: "Policy Violations",
+	},
 	"remove_page": {
 		Key:   "<x>",
 		Value: "Remove page",
@@ -145,6 +305,7 @@ const (
 	CreateTopicPageMenu      = "CreateTopicPageMenu"
 	CreateTopicInputMenu     = "CreateTopicInputMenu"
 	DeleteTopicPageMenu      = "DeleteTopicPageMenu"
+	TypedConfirmPageMenu     = "TypedConfirmPageMenu"
 	EditTopicPageMenu        = "EditTopicPageMenu"
 	EditTopicInputMenu       = "EditTopicInputMenu"
 	ConsumerGroupsPageMenu   = "ConsumerGroupsPageMenu"
@@ -153,6 +314,35 @@ const (
 	FinalPageMenu            = "FinalPageMenu"
 	CliTemplatesPageMenu     = "CliTemplatesPageMenu"
 	CliExecutePageMenu       = "CliExecutePageMenu"
+	ConsumeMessagesPageMenu  = "ConsumeMessagesPageMenu"
+	ConsumeMessagesInputMenu = "ConsumeMessagesInputMenu"
+	MessagesPageMenu         = "MessagesPageMenu"
+	ProduceMessagePageMenu   = "ProduceMessagePageMenu"
+	ProduceMessageInputMenu  = "ProduceMessageInputMenu"
+	PeekPageMenu             = "PeekPageMenu"
+	ConsumerGroupPageMenu    = "ConsumerGroupPageMenu"
+	ResetOffsetsPageMenu     = "ResetOffsetsPageMenu"
+	ResetOffsetsInputMenu    = "ResetOffsetsInputMenu"
+	OffsetsPreviewPageMenu   = "OffsetsPreviewPageMenu"
+	TopicPageMenu            = "TopicPageMenu"
+	DeleteRecordsPageMenu    = "DeleteRecordsPageMenu"
+	DeleteRecordsInputMenu   = "DeleteRecordsInputMenu"
+	NodePageMenu             = "NodePageMenu"
+	ElectLeadersPageMenu     = "ElectLeadersPageMenu"
+	ElectLeadersInputMenu    = "ElectLeadersInputMenu"
+	LeaderElectionPageMenu   = "LeaderElectionPageMenu"
+	ACLsPageMenu             = "ACLsPageMenu"
+	FormPageMenu             = "FormPageMenu"
+	FormInputMenu            = "FormInputMenu"
+	UsersPageMenu            = "UsersPageMenu"
+	LagOverviewPageMenu      = "LagOverviewPageMenu"
+	LagWatchPageMenu         = "LagWatchPageMenu"
+	HealthPageMenu           = "HealthPageMenu"
+	TopicsPlanPageMenu       = "TopicsPlanPageMenu"
+	ClusterDiffPageMenu      = "ClusterDiffPageMenu"
+	PolicyViolationsPageMenu = "PolicyViolationsPageMenu"
+	AuditLogPageMenu         = "AuditLogPageMenu"
+	AuditEntryPageMenu       = "AuditEntryPageMenu"
 )
 
 func NewMenu(colors *config.ColorConfig) *Menu {
@@ -173,6 +363,7 @@ func NewMenu(colors *config.ColorConfig) *Menu {
 			EditTopicPageMenu:    {"up", "dw", "select", "submit", "close"},
 			EditTopicInputMenu:   {"esc", "enter"},
 			DeleteTopicPageMenu:  {"confirm", "cancel"},
+			TypedConfirmPageMenu: {"enter", "cancel"},
 			CliTemplatesPageMenu: {"up", "dw", "copy_cli", "execute_cli", "close"},
 			ClustersPageMenu: {
 				"up",
@@ -181,6 +372,8 @@ func NewMenu(colors *config.ColorConfig) *Menu {
 				"res",
 				"opened",
 				"dsc",
+				"export_snapshot",
+				"compare_clusters",
 			},
 			SchemaRegistriesPageMenu: {
 				"up",
@@ -196,6 +389,17 @@ func NewMenu(colors *config.ColorConfig) *Menu {
 				"opened",
 				"dsc",
 				"upd",
+				"elect_preferred",
+				"broker_defaults",
+				"health",
+			},
+			HealthPageMenu: {
+				"up",
+				"dw",
+				"res",
+				"opened",
+				"dsc",
+				"upd",
 			},
 			TopicsPageMenu: {
 				"up",
@@ -209,8 +413,107 @@ func NewMenu(colors *config.ColorConfig) *Menu {
 				"delete",
 				"edit",
 				"cli_commands",
+				"messages",
+				"produce",
+				"peek",
+				"delete_records",
+				"elect_leaders",
+				"plan_manifest",
+				"policy_violations",
+			},
+			PolicyViolationsPageMenu: {
+				"up",
+				"dw",
+				"res",
+				"opened",
+				"dsc",
+				"upd",
+				"edit",
+			},
+			AuditLogPageMenu: {
+				"up",
+				"dw",
+				"res",
+				"opened",
+				"dsc",
+				"search",
+				"upd",
+			},
+			AuditEntryPageMenu: {"res", "opened"},
+			TopicsPlanPageMenu: {"res", "opened", "upd", "apply"},
+			ClusterDiffPageMenu: {
+				"up",
+				"dw",
+				"res",
+				"opened",
+				"upd",
+				"copy_right",
+				"copy_left",
 			},
 			CliExecutePageMenu: {"terminate_cli", "kill_cli", "remove_page"},
+			MessagesPageMenu: {
+				"up",
+				"dw",
+				"res",
+				"opened",
+				"upd",
+				"next_page",
+				"prev_page",
+				"messages",
+				"switch_focus",
+			},
+			PeekPageMenu: {
+				"up",
+				"dw",
+				"res",
+				"opened",
+				"upd",
+				"switch_focus",
+			},
+			ConsumeMessagesPageMenu:  {"up", "dw", "select", "submit", "close"},
+			ConsumeMessagesInputMenu: {"esc", "enter"},
+			ProduceMessagePageMenu:   {"up", "dw", "select", "submit", "close"},
+			ProduceMessageInputMenu:  {"esc", "enter"},
+			TopicPageMenu:            {"res", "opened", "upd", "delete_records", "elect_leaders"},
+			ElectLeadersPageMenu:     {"up", "dw", "select", "submit", "close"},
+			ElectLeadersInputMenu:    {"esc", "enter"},
+			LeaderElectionPageMenu:   {"up", "dw", "res", "opened"},
+			FormPageMenu:             {"up", "dw", "select", "submit", "close"},
+			FormInputMenu:            {"esc", "enter"},
+			DeleteRecordsPageMenu:    {"up", "dw", "select", "submit", "close"},
+			DeleteRecordsInputMenu:   {"esc", "enter"},
+			ACLsPageMenu: {
+				"up",
+				"dw",
+				"res",
+				"opened",
+				"upd",
+				"filter",
+				"create_acl",
+				"delete_acl",
+			},
+			UsersPageMenu: {
+				"up",
+				"dw",
+				"res",
+				"opened",
+				"upd",
+				"create_credential",
+				"update_credential",
+				"delete_credential",
+			},
+			NodePageMenu: {
+				"up",
+				"dw",
+				"res",
+				"opened",
+				"upd",
+				"edit_config",
+				"add_config",
+				"delete_override",
+				"broker_defaults",
+				"elect_preferred",
+			},
 			ConsumerGroupsPageMenu: {
 				"up",
 				"dw",
@@ -220,7 +523,33 @@ func NewMenu(colors *config.ColorConfig) *Menu {
 				"dsc",
 				"search",
 				"upd",
+				"mark",
+				"delete_cgroups",
+				"lag_overview",
 			},
+			LagOverviewPageMenu: {
+				"up",
+				"dw",
+				"res",
+				"opened",
+				"dsc",
+				"upd",
+				"sort",
+				"watch",
+			},
+			LagWatchPageMenu:      {"res", "opened", "pause"},
+			ConsumerGroupPageMenu: {"res", "opened", "upd", "reset_offsets", "watch"},
+			OffsetsPreviewPageMenu: {
+				"up",
+				"dw",
+				"res",
+				"opened",
+				"upd",
+				"apply",
+				"reset_offsets",
+			},
+			ResetOffsetsPageMenu:  {"up", "dw", "select", "submit", "close"},
+			ResetOffsetsInputMenu: {"esc", "enter"},
 			SubjectsPageMenu: {
 				"up",
 				"dw",
@@ -245,6 +574,7 @@ func NewMenu(colors *config.ColorConfig) *Menu {
 }
 
 func (m *Menu) SetMenu(menu string) {
+	m.current = menu
 	m.Content.Clear()
 	if keyBindings, ok := (*m.Map)[menu]; ok {
 		row := 0
@@ -253,6 +583,10 @@ func (m *Menu) SetMenu(menu string) {
 
 		for _, binding := range *keyBindings {
 			if value, exists := keys[binding]; exists {
+				if value.Mutating && m.ReadOnly {
+					continue
+				}
+
 				keyColor := m.Colors.Cinnamon.Keybinding.Key
 				valueColor := m.Colors.Cinnamon.Keybinding.Value
 
@@ -281,3 +615,24 @@ func (m *Menu) SetMenu(menu string) {
 		}
 	}
 }
+
+// SetReadOnly hides or shows the bindings of mutating actions and redraws the menu.
+func (m *Menu) SetReadOnly(readOnly bool) {
+	m.ReadOnly = readOnly
+	m.SetMenu(m.current)
+}
+
+// IsMutating reports whether a key label is bound to a mutating action in the
+// current menu.
+func (m *Menu) IsMutating(key string) bool {
+	keyBindings, ok := (*m.Map)[m.current]
+	if !ok {
+		return false
+	}
+	for _, binding := range *keyBindings {
+		if value, exists := keys[binding]; exists && value.Mutating && value.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,441 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+	"github.com/uraniumdawn/cinnamon/pkg/schemaregistry"
+	"github.com/uraniumdawn/cinnamon/pkg/util"
+)
+
+const (
+	defaultMessagesLimit = 100
+	messageCellWidth     = 60
+)
+
+// ConsumeMessages shows the form to choose partition and start position for browsing a topic.
+func (app *App) ConsumeMessages(topicName string) {
+	width := 40
+
+	partition := app.NewInputField("0", width)
+	partition.SetAcceptanceFunc(tview.InputFieldInteger)
+
+	options := make([]string, len(client.Positions))
+	for i, p := range client.Positions {
+		options[i] = string(p)
+	}
+	position := tview.NewDropDown().
+		SetOptions(options, nil).
+		SetCurrentOption(0).
+		SetFieldWidth(width).
+		SetFieldBackgroundColor(tcell.GetColor(app.Colors.Cinnamon.Label.BgColor))
+
+	from := app.NewInputField("Offset, RFC3339 or epoch millis timestamp", width)
+
+	limit := app.NewInputField(strconv.Itoa(defaultMessagesLimit), width)
+	limit.SetAcceptanceFunc(tview.InputFieldInteger)
+
+	selection := tview.NewTable()
+	selection.SetCell(0, 0, tview.NewTableCell("Partition:").SetAlign(tview.AlignRight))
+	selection.SetCell(1, 0, tview.NewTableCell("Start position:").SetAlign(tview.AlignRight))
+	selection.SetCell(2, 0, tview.NewTableCell("Offset/Timestamp:").SetAlign(tview.AlignRight))
+	selection.SetCell(3, 0, tview.NewTableCell("Limit:").SetAlign(tview.AlignRight))
+	selection.SetSelectable(true, false)
+	selection.SetBorderPadding(0, 0, 1, 0)
+	selection.SetSelectedStyle(
+		tcell.StyleDefault.Foreground(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.FgColor),
+		).Background(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.BgColor),
+		),
+	)
+
+	toSelection := func(key tcell.Key) {
+		app.SetFocus(selection)
+		app.Layout.Menu.SetMenu(ConsumeMessagesPageMenu)
+	}
+	partition.SetDoneFunc(toSelection)
+	position.SetDoneFunc(toSelection)
+	position.SetSelectedFunc(func(_ string, _ int) { toSelection(tcell.KeyEnter) })
+	from.SetDoneFunc(toSelection)
+	limit.SetDoneFunc(toSelection)
+	inputs := []tview.Primitive{partition, position, from, limit}
+
+	f := tview.NewFlex()
+	f.SetDirection(tview.FlexColumn)
+	f.AddItem(selection, 20, 0, true)
+	f.AddItem(tview.NewBox(), 3, 0, false)
+	f.AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(partition, 1, 0, false).
+		AddItem(position, 1, 0, false).
+		AddItem(from, 1, 0, false).
+		AddItem(limit, 1, 0, false).
+		AddItem(tview.NewBox(), 0, 1, false), width, 0, false).
+		AddItem(tview.NewBox(), 0, 1, false)
+
+	selection.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := selection.GetSelection()
+
+		if event.Key() == tcell.KeyEnter && row < len(inputs) {
+			app.SetFocus(inputs[row])
+			app.Layout.Menu.SetMenu(ConsumeMessagesInputMenu)
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 's' {
+			_, option := position.GetCurrentOption()
+			params := client.ConsumeParams{
+				Topic:     topicName,
+				Partition: max(util.GetInt32(partition), 0),
+				Position:  client.Position(option),
+				Limit:     defaultMessagesLimit,
+			}
+			if l := util.GetInt64(limit); l > 0 {
+				params.Limit = int(l)
+			}
+
+			text := strings.TrimSpace(from.GetText())
+			switch params.Position {
+			case client.PositionOffset:
+				offset, err := strconv.ParseInt(text, 10, 64)
+				if err != nil {
+					SendStatusWithDefaultTTL("[red]offset must be a number")
+					return event
+				}
+				params.Offset = offset
+			case client.PositionTimestamp:
+				ts, err := parseTimestamp(text)
+				if err != nil {
+					SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+					return event
+				}
+				params.Timestamp = ts
+			}
+
+			app.HideModalPage(ConsumeMessages)
+			app.Messages(params)
+		}
+
+		if event.Key() == tcell.KeyEsc {
+			app.HideModalPage(ConsumeMessages)
+		}
+
+		return event
+	})
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(f, 0, 1, true)
+	flex.SetTitle(fmt.Sprintf(" Browse Messages: %s ", topicName))
+	flex.SetBorder(true)
+
+	modal := util.NewTopicModal(flex)
+	app.Layout.PagesRegistry.UI.Pages.AddPage(ConsumeMessages, modal, true, false)
+	app.ShowModalPage(ConsumeMessages)
+}
+
+// Messages reads a page of records and displays it in the message browser.
+func (app *App) Messages(params client.ConsumeParams) {
+	// Buffered so that a result arriving after the timeout does not block the consumer.
+	resultCh := make(chan *client.RecordsResult, 1)
+	errorCh := make(chan error, 1)
+
+	c, err := app.GetCurrentKafkaConsumer()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to create consumer")
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to create consumer: %s", err.Error()))
+		return
+	}
+	SendStatusInfinite("consuming messages")
+	c.Consume(params, resultCh, errorCh)
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
+
+	go func() {
+		for {
+			select {
+			case result := <-resultCh:
+				decodeErr := app.decodeRecords(result.Records)
+				app.QueueUpdateDraw(func() {
+					page := app.NewMessagesPage(result)
+					app.AddToPagesRegistry(
+						util.BuildPageKey(app.Selected.Cluster.Name, Topic, params.Topic, Messages),
+						page,
+						MessagesPageMenu, false,
+					)
+					switch {
+					case decodeErr != nil:
+						SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", decodeErr.Error()))
+					case len(result.Records) == 0:
+						SendStatusWithDefaultTTL("no messages in the selected range")
+					default:
+						ClearStatus()
+					}
+				})
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to consume messages")
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to consume messages: %s", err.Error()))
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while consuming messages")
+				SendStatusWithDefaultTTL("[red]timeout while consuming messages")
+				return
+			}
+		}
+	}()
+}
+
+// NewMessagesPage creates the message browser with the records table and a detail pane.
+func (app *App) NewMessagesPage(result *client.RecordsResult) tview.Primitive {
+	params := result.Params
+
+	table, page := app.NewRecordsView(result.Records)
+	table.SetTitle(util.BuildTitle(
+		Messages,
+		params.Topic,
+		strconv.Itoa(int(params.Partition)),
+		fmt.Sprintf("%d..%d of %d..%d", result.Start, result.Next, result.Low, result.High),
+	))
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlU {
+			app.Messages(params)
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'n' {
+			if result.Next >= result.High {
+				SendStatusWithDefaultTTL("already at the end of the partition")
+				return nil
+			}
+			next := params
+			next.Position = client.PositionOffset
+			next.Offset = result.Next
+			app.Messages(next)
+			return nil
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'b' {
+			if result.Start <= result.Low {
+				SendStatusWithDefaultTTL("already at the beginning of the partition")
+				return nil
+			}
+			prev := params
+			prev.Position = client.PositionOffset
+			prev.Offset = max(result.Low, result.Start-int64(params.Limit))
+			app.Messages(prev)
+			return nil
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'm' {
+			app.ConsumeMessages(params.Topic)
+			return nil
+		}
+
+		return event
+	})
+
+	return page
+}
+
+// Peek reads the latest records of every partition of a topic.
+func (app *App) Peek(topicName string) {
+	// Buffered so that a result arriving after the timeout does not block the consumer.
+	resultCh := make(chan *client.PeekResult, 1)
+	errorCh := make(chan error, 1)
+
+	c, err := app.GetCurrentKafkaConsumer()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to create consumer")
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to create consumer: %s", err.Error()))
+		return
+	}
+	SendStatusInfinite("peeking messages")
+	c.Peek(topicName, app.Config.GetPeekRecords(), resultCh, errorCh)
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
+
+	go func() {
+		for {
+			select {
+			case result := <-resultCh:
+				decodeErr := app.decodeRecords(result.Records)
+				app.QueueUpdateDraw(func() {
+					table, page := app.NewRecordsView(result.Records)
+					table.SetTitle(util.BuildTitle(
+						"peek",
+						topicName,
+						"last "+strconv.Itoa(result.Limit),
+						"["+strconv.Itoa(len(result.Records))+"]",
+					))
+					table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+						if event.Key() == tcell.KeyCtrlU {
+							app.Peek(topicName)
+						}
+						return event
+					})
+					app.AddToPagesRegistry(
+						util.BuildPageKey(app.Selected.Cluster.Name, Topic, topicName, "peek"),
+						page,
+						PeekPageMenu, false,
+					)
+					switch {
+					case decodeErr != nil:
+						SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", decodeErr.Error()))
+					case len(result.Records) == 0:
+						SendStatusWithDefaultTTL("topic is empty")
+					default:
+						ClearStatus()
+					}
+				})
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to peek messages")
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to peek messages: %s", err.Error()))
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while peeking messages")
+				SendStatusWithDefaultTTL("[red]timeout while peeking messages")
+				return
+			}
+		}
+	}()
+}
+
+// NewRecordsView creates a records table with a detail pane for the selected record.
+// Tab switches focus between the table and the detail pane.
+func (app *App) NewRecordsView(records []client.Record) (*tview.Table, *tview.Flex) {
+	table := tview.NewTable()
+	table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetBorderPadding(0, 0, 1, 0)
+	table.SetSelectedStyle(
+		tcell.StyleDefault.Foreground(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.FgColor),
+		).Background(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.BgColor),
+		),
+	)
+
+	for i, h := range []string{"Partition", "Offset", "Timestamp", "Key", "Value", "Headers"} {
+		table.SetCell(0, i, tview.NewTableCell(h).
+			SetTextColor(tcell.GetColor(app.Colors.Cinnamon.Label.FgColor)).
+			SetSelectable(false))
+	}
+
+	for i, r := range records {
+		headers := make([]string, len(r.Headers))
+		for j, h := range r.Headers {
+			headers[j] = h.Key + "=" + client.FormatBytes(h.Value)
+		}
+		row := i + 1
+		table.SetCell(row, 0, tview.NewTableCell(strconv.Itoa(int(r.Partition))))
+		table.SetCell(row, 1, tview.NewTableCell(strconv.FormatInt(int64(r.Offset), 10)))
+		table.SetCell(row, 2, tview.NewTableCell(r.Timestamp.Format(time.RFC3339)))
+		table.SetCell(row, 3, tview.NewTableCell(toCellText(r.KeyString())))
+		table.SetCell(row, 4, tview.NewTableCell(toCellText(r.ValueString())).
+			SetExpansion(1))
+		table.SetCell(row, 5, tview.NewTableCell(toCellText(strings.Join(headers, ", "))))
+	}
+
+	detail := app.NewDescription(" record ")
+	table.SetSelectionChangedFunc(func(row, _ int) {
+		if row < 1 || row > len(records) {
+			detail.Clear()
+			return
+		}
+		detail.SetText(tview.Escape(records[row-1].String()))
+		detail.ScrollToBeginning()
+	})
+	if len(records) > 0 {
+		table.Select(1, 0)
+	}
+
+	page := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(table, 0, 1, true).
+		AddItem(detail, 0, 1, false)
+
+	page.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyTab {
+			if table.HasFocus() {
+				app.SetFocus(detail)
+			} else {
+				app.SetFocus(table)
+			}
+			return nil
+		}
+		return event
+	})
+
+	return table, page
+}
+
+// decodeRecords renders schema encoded keys and values using the selected
+// Schema Registry. It returns an error describing the records that failed to decode.
+func (app *App) decodeRecords(records []client.Record) error {
+	sr := app.GetCurrentSchemaRegistryClient()
+	if sr == nil {
+		return nil
+	}
+
+	var failed int
+	var firstErr error
+	decode := func(data []byte) string {
+		if _, _, ok := schemaregistry.ParseWireFormat(data); !ok {
+			return ""
+		}
+		decoded, err := sr.Decode(data)
+		if err != nil {
+			log.Warn().Err(err).Msg("failed to decode record")
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		return decoded
+	}
+
+	for i := range records {
+		records[i].DecodedKey = decode(records[i].Key)
+		records[i].DecodedValue = decode(records[i].Value)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to decode %d keys or values: %w", failed, firstErr)
+	}
+	return nil
+}
+
+func toCellText(text string) string {
+	text = strings.ReplaceAll(text, "\n", " ")
+	if r := []rune(text); len(r) > messageCellWidth {
+		text = string(r[:messageCellWidth-3]) + "..."
+	}
+	return tview.Escape(text)
+}
+
+func parseTimestamp(text string) (int64, error) {
+	if ms, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return ms, nil
+	}
+	t, err := time.Parse(time.RFC3339, text)
+	if err != nil {
+		return 0, fmt.Errorf("timestamp must be epoch millis or RFC3339")
+	}
+	return t.UnixMilli(), nil
+}
@@ -43,6 +43,11 @@ func (app *App) RunNodesEventHandler(ctx context.Context, in chan Event) {
 				log.Debug().Msg("shutting down nodes event handler")
 				return
 			case event := <-in:
+				// Events of this handler read the selected cluster.
+				if !app.isClusterSelected(app.Selected) {
+					SendStatusWithDefaultTTL("[red]to perform operation, select cluster")
+					continue
+				}
 				switch event.Type {
 				case GetNodesEventType:
 					pageName := util.BuildPageKey(app.Selected.Cluster.Name, Nodes)
@@ -54,16 +59,23 @@ func (app *App) RunNodesEventHandler(ctx context.Context, in chan Event) {
 						app.Nodes()
 					}
 				case GetNodeEventType:
-					nu := event.Payload.Data.(NodeIDURLPair)
+					node := event.Payload.Data.(NodeIDURLPair)
+					force := event.Payload.Force
+					pageName := nodePageKey(app.Selected.Cluster.Name, node)
+					_, found := app.Cache.Get(pageName)
+					if found && !force {
+						app.SwitchToPage(pageName)
+					} else {
+						app.Node(node)
+					}
+				case GetHealthEventType:
+					pageName := util.BuildPageKey(app.Selected.Cluster.Name, Health)
 					force := event.Payload.Force
-					nodeID := nu.ID
-					url := nu.URL
-					pageName := util.BuildPageKey(app.Selected.Cluster.Name, Nodes, nodeID)
 					_, found := app.Cache.Get(pageName)
 					if found && !force {
 						app.SwitchToPage(pageName)
 					} else {
-						app.Node(nodeID, url)
+						app.Health()
 					}
 				}
 			}
@@ -76,7 +88,11 @@ func (app *App) Nodes() {
 	resultCh := make(chan *client.ClusterResult)
 	errorCh := make(chan error)
 
-	c := app.GetCurrentKafkaClient()
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
 	SendStatusInfinite("getting nodes...")
 	c.DescribeCluster(resultCh, errorCh)
 	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
@@ -101,6 +117,18 @@ func (app *App) Nodes() {
 								Payload{Data: NodeIDURLPair{nodeID, url}, Force: false})
 						}
 
+						if event.Key() == tcell.KeyRune && event.Rune() == 'L' {
+							app.ConfirmClusterElection()
+						}
+
+						if event.Key() == tcell.KeyRune && event.Rune() == 'D' {
+							Publish(NodesChannel, GetNodeEventType, Payload{clusterDefaults, false})
+						}
+
+						if event.Key() == tcell.KeyRune && event.Rune() == 'H' {
+							Publish(NodesChannel, GetHealthEventType, Payload{nil, false})
+						}
+
 						return event
 					})
 
@@ -129,14 +157,19 @@ func (app *App) Nodes() {
 	}()
 }
 
-// Node fetches and displays details for a specific Kafka node.
-func (app *App) Node(id, url string) {
+// Node fetches and displays the editable config table of a Kafka node or of
+// the cluster-wide broker defaults.
+func (app *App) Node(node NodeIDURLPair) {
 	resultCh := make(chan *client.ResourceResult)
 	errorCh := make(chan error)
 
-	c := app.GetCurrentKafkaClient()
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
 	SendStatusInfinite("getting node description")
-	c.DescribeNode(id, resultCh, errorCh)
+	c.DescribeNode(node.ID, resultCh, errorCh)
 	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
 
 	go func() {
@@ -144,22 +177,30 @@ func (app *App) Node(id, url string) {
 			select {
 			case description := <-resultCh:
 				app.QueueUpdateDraw(func() {
-					desc := app.NewDescription(util.BuildTitle(Node, url, id))
-					desc.SetText(description.String())
-					desc.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-						if event.Key() == tcell.KeyCtrlU {
-							Publish(
-								NodesChannel,
-								GetNodeEventType,
-								Payload{NodeIDURLPair{id, url}, true},
-							)
+					table := app.NewBrokerConfigTable(node, description)
+					configCapture := table.GetInputCapture()
+					table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+						if event.Key() == tcell.KeyRune && event.Rune() == 'L' &&
+							node.ID != client.ClusterDefaultBroker {
+							broker, err := strconv.Atoi(node.ID)
+							if err != nil {
+								SendStatusWithDefaultTTL(
+									fmt.Sprintf("[red]invalid broker id '%s'", node.ID),
+								)
+								return event
+							}
+							app.ElectLeadersResultHandler(client.ElectLeadersParams{
+								Type:   kafka.ElectionTypePreferred,
+								Broker: int32(broker),
+							})
+							return event
 						}
-						return event
+						return configCapture(event)
 					})
 					app.AddToPagesRegistry(
-						util.BuildPageKey(app.Selected.Cluster.Name, Node, id),
-						desc,
-						FinalPageMenu, false,
+						nodePageKey(app.Selected.Cluster.Name, node),
+						table,
+						NodePageMenu, false,
 					)
 					ClearStatus()
 				})
@@ -0,0 +1,343 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+	"github.com/uraniumdawn/cinnamon/pkg/util"
+)
+
+// ResetOffsets shows the form to choose the strategy and scope of a consumer group offset reset.
+func (app *App) ResetOffsets(group string) {
+	width := 40
+
+	options := make([]string, len(client.ResetStrategies))
+	for i, s := range client.ResetStrategies {
+		options[i] = string(s)
+	}
+	strategy := tview.NewDropDown().
+		SetOptions(options, nil).
+		SetCurrentOption(0).
+		SetFieldWidth(width).
+		SetFieldBackgroundColor(tcell.GetColor(app.Colors.Cinnamon.Label.BgColor))
+
+	topic := app.NewInputField("Empty for all topics", width)
+	partitions := app.NewInputField("Comma separated, empty for all", width)
+	value := app.NewInputField("Offset, shift, RFC3339 or epoch millis", width)
+
+	selection := tview.NewTable()
+	selection.SetCell(0, 0, tview.NewTableCell("Strategy:").SetAlign(tview.AlignRight))
+	selection.SetCell(1, 0, tview.NewTableCell("Topic:").SetAlign(tview.AlignRight))
+	selection.SetCell(2, 0, tview.NewTableCell("Partitions:").SetAlign(tview.AlignRight))
+	selection.SetCell(3, 0, tview.NewTableCell("Offset/Shift/Time:").SetAlign(tview.AlignRight))
+	selection.SetSelectable(true, false)
+	selection.SetBorderPadding(0, 0, 1, 0)
+	selection.SetSelectedStyle(
+		tcell.StyleDefault.Foreground(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.FgColor),
+		).Background(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.BgColor),
+		),
+	)
+
+	toSelection := func(key tcell.Key) {
+		app.SetFocus(selection)
+		app.Layout.Menu.SetMenu(ResetOffsetsPageMenu)
+	}
+	strategy.SetDoneFunc(toSelection)
+	strategy.SetSelectedFunc(func(_ string, _ int) { toSelection(tcell.KeyEnter) })
+	topic.SetDoneFunc(toSelection)
+	partitions.SetDoneFunc(toSelection)
+	value.SetDoneFunc(toSelection)
+	inputs := []tview.Primitive{strategy, topic, partitions, value}
+
+	f := tview.NewFlex()
+	f.SetDirection(tview.FlexColumn)
+	f.AddItem(selection, 20, 0, true)
+	f.AddItem(tview.NewBox(), 3, 0, false)
+	f.AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(strategy, 1, 0, false).
+		AddItem(topic, 1, 0, false).
+		AddItem(partitions, 1, 0, false).
+		AddItem(value, 1, 0, false).
+		AddItem(tview.NewBox(), 0, 1, false), width, 0, false).
+		AddItem(tview.NewBox(), 0, 1, false)
+
+	selection.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := selection.GetSelection()
+
+		if event.Key() == tcell.KeyEnter && row < len(inputs) {
+			app.SetFocus(inputs[row])
+			app.Layout.Menu.SetMenu(ResetOffsetsInputMenu)
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 's' {
+			_, option := strategy.GetCurrentOption()
+			params := client.ResetOffsetsParams{
+				Group:    group,
+				Strategy: client.ResetStrategy(option),
+				Topic:    strings.TrimSpace(topic.GetText()),
+			}
+
+			parsed, err := parsePartitions(partitions.GetText())
+			if err != nil {
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+				return event
+			}
+			if len(parsed) > 0 && params.Topic == "" {
+				SendStatusWithDefaultTTL("[red]partitions require a topic")
+				return event
+			}
+			params.Partitions = parsed
+
+			text := strings.TrimSpace(value.GetText())
+			switch params.Strategy {
+			case client.ResetToOffset, client.ResetShiftBy:
+				v, err := strconv.ParseInt(text, 10, 64)
+				if err != nil {
+					SendStatusWithDefaultTTL("[red]offset or shift must be a number")
+					return event
+				}
+				params.Value = v
+			case client.ResetToTimestamp:
+				ts, err := parseTimestamp(text)
+				if err != nil {
+					SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+					return event
+				}
+				params.Value = ts
+			}
+
+			app.HideModalPage(ResetOffsets)
+			app.OffsetsPreview(params)
+		}
+
+		if event.Key() == tcell.KeyEsc {
+			app.HideModalPage(ResetOffsets)
+		}
+
+		return event
+	})
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(f, 0, 1, true)
+	flex.SetTitle(fmt.Sprintf(" Reset Offsets: %s ", group))
+	flex.SetBorder(true)
+
+	modal := util.NewTopicModal(flex)
+	app.Layout.PagesRegistry.UI.Pages.AddPage(ResetOffsets, modal, true, false)
+	app.ShowModalPage(ResetOffsets)
+}
+
+// OffsetsPreview computes an offset reset without committing it and shows
+// the current and new offsets of every affected partition.
+func (app *App) OffsetsPreview(params client.ResetOffsetsParams) {
+	resultCh := make(chan *client.ResetOffsetsPlan)
+	errorCh := make(chan error)
+
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	SendStatusInfinite("computing offsets")
+	c.PlanOffsetReset(params, resultCh, errorCh)
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
+
+	go func() {
+		for {
+			select {
+			case plan := <-resultCh:
+				app.QueueUpdateDraw(func() {
+					table := app.NewOffsetsPreviewTable(plan)
+					app.AddToPagesRegistry(
+						util.BuildPageKey(
+							app.Selected.Cluster.Name,
+							ConsumerGroup,
+							params.Group,
+							ResetOffsets,
+						),
+						table,
+						OffsetsPreviewPageMenu, false,
+					)
+					if plan.Applicable() {
+						ClearStatus()
+					} else {
+						SendStatusWithDefaultTTL(fmt.Sprintf(
+							"[red]group is %s, stop its consumers to apply the reset",
+							plan.State.String(),
+						))
+					}
+				})
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to compute offsets")
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to compute offsets: %s", err.Error()))
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while computing offsets")
+				SendStatusWithDefaultTTL("[red]timeout while computing offsets")
+				return
+			}
+		}
+	}()
+}
+
+// NewOffsetsPreviewTable creates a table of current and new offsets of an offset reset plan.
+func (app *App) NewOffsetsPreviewTable(plan *client.ResetOffsetsPlan) *tview.Table {
+	params := plan.Params
+
+	table := tview.NewTable()
+	table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetBorderPadding(0, 0, 1, 0)
+	table.SetSelectedStyle(
+		tcell.StyleDefault.Foreground(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.FgColor),
+		).Background(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.BgColor),
+		),
+	)
+	table.SetTitle(util.BuildTitle(
+		ResetOffsets,
+		params.Group,
+		string(params.Strategy),
+		plan.State.String(),
+	))
+
+	for i, h := range []string{"Topic", "Partition", "Current", "New", "Change", "Lag"} {
+		table.SetCell(0, i, tview.NewTableCell(h).
+			SetTextColor(tcell.GetColor(app.Colors.Cinnamon.Label.FgColor)).
+			SetSelectable(false))
+	}
+
+	for i, r := range plan.Resets {
+		current, change := "-", "-"
+		if r.Current >= 0 {
+			current = strconv.FormatInt(int64(r.Current), 10)
+			change = fmt.Sprintf("%+d", int64(r.New-r.Current))
+		}
+		row := i + 1
+		table.SetCell(row, 0, tview.NewTableCell(r.Topic))
+		table.SetCell(row, 1, tview.NewTableCell(strconv.Itoa(int(r.Partition))))
+		table.SetCell(row, 2, tview.NewTableCell(current))
+		table.SetCell(row, 3, tview.NewTableCell(strconv.FormatInt(int64(r.New), 10)))
+		table.SetCell(row, 4, tview.NewTableCell(change))
+		table.SetCell(row, 5, tview.NewTableCell(strconv.FormatInt(r.Lag(), 10)))
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlU {
+			app.OffsetsPreview(params)
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'R' {
+			app.ResetOffsets(params.Group)
+			return nil
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 's' {
+			if !plan.Applicable() {
+				SendStatusWithDefaultTTL(fmt.Sprintf(
+					"[red]offsets can only be reset for an Empty group, group is %s",
+					plan.State.String(),
+				))
+				return nil
+			}
+			app.ConfirmResetOffsets(plan)
+			return nil
+		}
+
+		return event
+	})
+
+	return table
+}
+
+// ConfirmResetOffsets asks for confirmation before committing the new offsets of
+// a plan. On a protected cluster the group name must be typed.
+func (app *App) ConfirmResetOffsets(plan *client.ResetOffsetsPlan) {
+	group := plan.Params.Group
+	message := fmt.Sprintf(
+		"Reset [red::b]%d[-::-] offsets of group [red::b]%s[-::-]. Confirm?",
+		len(plan.Resets),
+		tview.Escape(group),
+	)
+	app.confirmDestructive(ConfirmResetOffsets, " Confirm Reset ", message, group, func() {
+		app.ResetOffsetsResultHandler(plan)
+	})
+}
+
+// ResetOffsetsResultHandler commits the new offsets of a plan.
+func (app *App) ResetOffsetsResultHandler(plan *client.ResetOffsetsPlan) {
+	resultCh := make(chan bool)
+	errorCh := make(chan error)
+
+	group := plan.Params.Group
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	SendStatusInfinite("resetting offsets")
+	c.ResetOffsets(plan, resultCh, errorCh)
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
+
+	go func() {
+		for {
+			select {
+			case <-resultCh:
+				SendStatus(
+					fmt.Sprintf("offsets of group '%s' have been reset", group),
+					2*time.Second,
+					false,
+				)
+				Publish(CgroupsChannel, GetCgroupEventType, Payload{group, true})
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to reset offsets")
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to reset offsets: %s", err.Error()))
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while resetting offsets")
+				SendStatusWithDefaultTTL("[red]timeout while resetting offsets")
+				return
+			}
+		}
+	}()
+}
+
+// parsePartitions parses a comma separated list of partition numbers.
+func parsePartitions(text string) ([]int32, error) {
+	var partitions []int32
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		p, err := strconv.ParseInt(part, 10, 32)
+		if err != nil || p < 0 {
+			return nil, fmt.Errorf("invalid partition '%s'", part)
+		}
+		partitions = append(partitions, int32(p))
+	}
+	return partitions, nil
+}
@@ -68,6 +68,26 @@ func (pr *PagesRegistry) SetupPageMenus() {
 	pr.PageMenuMap[DeleteTopic] = DeleteTopicPageMenu
 	pr.PageMenuMap[EditTopic] = EditTopicPageMenu
 	pr.PageMenuMap[CliTemplates] = CliTemplatesPageMenu
+	pr.PageMenuMap[ConsumeMessages] = ConsumeMessagesPageMenu
+	pr.PageMenuMap[ProduceMessage] = ProduceMessagePageMenu
+	pr.PageMenuMap[ResetOffsets] = ResetOffsetsPageMenu
+	pr.PageMenuMap[DeleteConsumerGroups] = DeleteTopicPageMenu
+	pr.PageMenuMap[DeleteRecords] = DeleteRecordsPageMenu
+	pr.PageMenuMap[ConfirmDeleteRecords] = DeleteTopicPageMenu
+	pr.PageMenuMap[ElectLeaders] = ElectLeadersPageMenu
+	pr.PageMenuMap[ConfirmUncleanElection] = DeleteTopicPageMenu
+	pr.PageMenuMap[FilterACLs] = FormPageMenu
+	pr.PageMenuMap[CreateACL] = FormPageMenu
+	pr.PageMenuMap[DeleteACL] = DeleteTopicPageMenu
+	pr.PageMenuMap[UpsertCredential] = FormPageMenu
+	pr.PageMenuMap[DeleteCredential] = DeleteTopicPageMenu
+	pr.PageMenuMap[EditBrokerConfig] = FormPageMenu
+	pr.PageMenuMap[DeleteBrokerConfig] = DeleteTopicPageMenu
+	pr.PageMenuMap[PlanManifest] = FormPageMenu
+	pr.PageMenuMap[ConfirmApplyPlan] = DeleteTopicPageMenu
+	pr.PageMenuMap[ExportSnapshot] = FormPageMenu
+	pr.PageMenuMap[CompareClusters] = FormPageMenu
+	pr.PageMenuMap[CopyTopic] = DeleteTopicPageMenu
 }
 
 func (app *App) CheckInCache(name string, onAbsent func()) {
@@ -288,3 +308,17 @@ func (app *App) RemoveFromPagesRegistry(name string) {
 		app.SwitchToPage(registry.History[registry.CurrentPageIndex])
 	}
 }
+
+// pageState reports whether the page is still registered with the given component
+// and whether it is the front page. Pollers of a page stop once it is not shown.
+// It must not be called from the UI goroutine.
+func (app *App) pageState(pageName string, component tview.Primitive) (shown, front bool) {
+	state := make(chan [2]bool, 1)
+	app.QueueUpdate(func() {
+		pages := app.Layout.PagesRegistry.UI.Pages
+		frontName, _ := pages.GetFrontPage()
+		state <- [2]bool{pages.GetPage(pageName) == component, frontName == pageName}
+	})
+	s := <-state
+	return s[0], s[1]
+}
@@ -0,0 +1,155 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/policy"
+	"github.com/uraniumdawn/cinnamon/pkg/util"
+)
+
+// GetPolicyViolationsEventType is the event type for checking all topics against the
+// policy rules of the cluster.
+const GetPolicyViolationsEventType EventType = "topics:policy"
+
+// topicPolicies parses the policy rules of the selected cluster.
+func (app *App) topicPolicies() ([]policy.Rule, error) {
+	return policy.ParseAll(app.Selected.Cluster.Policies)
+}
+
+// violationsError reports the first violation and how many more there are.
+func violationsError(violations []policy.Violation) error {
+	if len(violations) == 1 {
+		return violations[0]
+	}
+	return fmt.Errorf("%w (and %d more violations)", violations[0], len(violations)-1)
+}
+
+// checkTopicEdit checks an edited topic against the rules, see policy.CheckEdit.
+func checkTopicEdit(
+	rules []policy.Rule,
+	edited policy.Topic,
+	partitionCount int,
+	editedConfig map[string]string,
+) error {
+	if violations := policy.CheckEdit(rules, edited, partitionCount, editedConfig); len(violations) > 0 {
+		return violationsError(violations)
+	}
+	return nil
+}
+
+// PolicyViolations checks every topic of the selected cluster against its policy
+// rules and displays the violations.
+func (app *App) PolicyViolations() {
+	rules, err := app.topicPolicies()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	if len(rules) == 0 {
+		SendStatusWithDefaultTTL(fmt.Sprintf(
+			"no policies are configured for cluster '%s'",
+			app.Selected.Cluster.Name,
+		))
+		return
+	}
+
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	cluster := app.Selected.Cluster.Name
+	SendStatusInfinite("checking topic policies")
+
+	go func() {
+		report, err := policy.NewReport(c, rules)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to check topic policies")
+			SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to check topic policies: %s", err.Error()))
+			return
+		}
+
+		app.QueueUpdateDraw(func() {
+			table := app.NewPolicyViolationsTable(rules, report)
+			app.AddToPagesRegistry(
+				util.BuildPageKey(cluster, PolicyViolations),
+				table,
+				PolicyViolationsPageMenu, false,
+			)
+			ClearStatus()
+		})
+	}()
+}
+
+// NewPolicyViolationsTable creates a table of the topics violating the policy rules.
+func (app *App) NewPolicyViolationsTable(rules []policy.Rule, report *policy.Report) *tview.Table {
+	table := tview.NewTable()
+	table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetBorderPadding(0, 0, 1, 0)
+	table.SetSelectedStyle(
+		tcell.StyleDefault.Foreground(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.FgColor),
+		).Background(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.BgColor),
+		),
+	)
+
+	topics := make(map[string]struct{})
+	for _, v := range report.Violations {
+		topics[v.Topic] = struct{}{}
+	}
+	table.SetTitle(util.BuildTitle(
+		PolicyViolations,
+		fmt.Sprintf("%d of %d topics", len(topics), report.Topics),
+		fmt.Sprintf("%d rules", len(rules)),
+	))
+
+	headers := []string{"Topic", "Rule", "Actual"}
+	for i, h := range headers {
+		table.SetCell(0, i, tview.NewTableCell(h).
+			SetTextColor(tcell.GetColor(app.Colors.Cinnamon.Label.FgColor)).
+			SetSelectable(false))
+	}
+
+	for i, v := range report.Violations {
+		table.SetCell(i+1, 0, tview.NewTableCell(v.Topic))
+		table.SetCell(i+1, 1, tview.NewTableCell(tview.Escape(v.Rule.String())))
+		table.SetCell(i+1, 2, tview.NewTableCell(tview.Escape(v.Actual)).
+			SetTextColor(tcell.ColorRed).
+			SetExpansion(1))
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlU {
+			Publish(TopicsChannel, GetPolicyViolationsEventType, Payload{nil, true})
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'd' {
+			row, _ := table.GetSelection()
+			if row > 0 {
+				Publish(TopicsChannel, GetTopicEventType, Payload{table.GetCell(row, 0).Text, false})
+			}
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'e' {
+			row, _ := table.GetSelection()
+			if row > 0 {
+				app.UpdateTopic(table.GetCell(row, 0).Text)
+			}
+		}
+
+		return event
+	})
+
+	return table
+}
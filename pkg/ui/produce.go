@@ -0,0 +1,229 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+	"github.com/uraniumdawn/cinnamon/pkg/util"
+)
+
+// ProduceMessage shows the form to send records to a topic.
+func (app *App) ProduceMessage(topicName string) {
+	width := 40
+
+	key := app.NewInputField("Optional record key", width)
+
+	partition := app.NewInputField("Empty to use partitioner", width)
+	partition.SetAcceptanceFunc(tview.InputFieldInteger)
+
+	partitioner := tview.NewDropDown().
+		SetOptions(client.Partitioners, nil).
+		SetCurrentOption(0).
+		SetFieldWidth(width).
+		SetFieldBackgroundColor(tcell.GetColor(app.Colors.Cinnamon.Label.BgColor))
+
+	copies := app.NewInputField("1", width)
+	copies.SetAcceptanceFunc(tview.InputFieldInteger)
+
+	headers := tview.NewTextArea().
+		SetPlaceholder(`Enter headers (one per line):
+trace-id=abc`).
+		SetPlaceholderStyle(
+			tcell.StyleDefault.Foreground(
+				tcell.GetColor(app.Colors.Cinnamon.Placeholder),
+			))
+
+	value := tview.NewTextArea().
+		SetPlaceholder("Enter record value").
+		SetPlaceholderStyle(
+			tcell.StyleDefault.Foreground(
+				tcell.GetColor(app.Colors.Cinnamon.Placeholder),
+			))
+
+	selection := tview.NewTable()
+	selection.SetCell(0, 0, tview.NewTableCell("Key:").SetAlign(tview.AlignRight))
+	selection.SetCell(1, 0, tview.NewTableCell("Partition:").SetAlign(tview.AlignRight))
+	selection.SetCell(2, 0, tview.NewTableCell("Partitioner:").SetAlign(tview.AlignRight))
+	selection.SetCell(3, 0, tview.NewTableCell("Copies:").SetAlign(tview.AlignRight))
+	selection.SetCell(4, 0, tview.NewTableCell("Headers:").SetAlign(tview.AlignRight))
+	selection.SetCell(5, 0, tview.NewTableCell("").SetSelectable(false))
+	selection.SetCell(6, 0, tview.NewTableCell("").SetSelectable(false))
+	selection.SetCell(7, 0, tview.NewTableCell("Value:").SetAlign(tview.AlignRight))
+	selection.SetSelectable(true, false)
+	selection.SetBorderPadding(0, 0, 1, 0)
+	selection.SetSelectedStyle(
+		tcell.StyleDefault.Foreground(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.FgColor),
+		).Background(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.BgColor),
+		),
+	)
+
+	toSelection := func(_ tcell.Key) {
+		app.SetFocus(selection)
+		app.Layout.Menu.SetMenu(ProduceMessagePageMenu)
+	}
+	key.SetDoneFunc(toSelection)
+	partition.SetDoneFunc(toSelection)
+	partitioner.SetDoneFunc(toSelection)
+	partitioner.SetSelectedFunc(func(_ string, _ int) { toSelection(tcell.KeyEnter) })
+	copies.SetDoneFunc(toSelection)
+
+	for _, area := range []*tview.TextArea{headers, value} {
+		area.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if event.Key() == tcell.KeyEsc {
+				toSelection(tcell.KeyEsc)
+				return nil
+			}
+			return event
+		})
+	}
+
+	inputs := map[int]tview.Primitive{
+		0: key,
+		1: partition,
+		2: partitioner,
+		3: copies,
+		4: headers,
+		7: value,
+	}
+
+	f := tview.NewFlex()
+	f.SetDirection(tview.FlexColumn)
+	f.AddItem(selection, 20, 0, true)
+	f.AddItem(tview.NewBox(), 3, 0, false)
+	f.AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(key, 1, 0, false).
+		AddItem(partition, 1, 0, false).
+		AddItem(partitioner, 1, 0, false).
+		AddItem(copies, 1, 0, false).
+		AddItem(headers, 3, 0, false).
+		AddItem(value, 0, 1, false), 0, 1, false)
+
+	selection.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := selection.GetSelection()
+
+		if event.Key() == tcell.KeyEnter {
+			if input, ok := inputs[row]; ok {
+				app.SetFocus(input)
+				app.Layout.Menu.SetMenu(ProduceMessageInputMenu)
+			}
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 's' {
+			params := client.ProduceParams{
+				Topic:     topicName,
+				Value:     []byte(value.GetText()),
+				Headers:   parseHeaders(headers.GetText()),
+				Partition: kafka.PartitionAny,
+				Count:     1,
+			}
+			if k := key.GetText(); k != "" {
+				params.Key = []byte(k)
+			}
+			if p := util.GetInt32(partition); p >= 0 {
+				params.Partition = p
+			}
+			if c := util.GetInt64(copies); c > 0 {
+				params.Count = int(c)
+			}
+			_, option := partitioner.GetCurrentOption()
+
+			app.ProduceResultHandler(params, option)
+			app.HideModalPage(ProduceMessage)
+		}
+
+		if event.Key() == tcell.KeyEsc {
+			app.HideModalPage(ProduceMessage)
+		}
+
+		return event
+	})
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(f, 0, 1, true)
+	flex.SetTitle(fmt.Sprintf(" Produce Message: %s ", topicName))
+	flex.SetBorder(true)
+
+	modal := util.NewTopicModal(flex)
+	app.Layout.PagesRegistry.UI.Pages.AddPage(ProduceMessage, modal, true, false)
+	app.ShowModalPage(ProduceMessage)
+}
+
+// ProduceResultHandler sends records and shows their delivery reports in the status line.
+func (app *App) ProduceResultHandler(params client.ProduceParams, partitioner string) {
+	resultCh := make(chan *client.ProduceResult)
+	errorCh := make(chan error)
+
+	p, err := app.GetCurrentKafkaProducer(partitioner)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to create producer")
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to create producer: %s", err.Error()))
+		return
+	}
+	SendStatusInfinite("producing messages")
+	p.Produce(params, resultCh, errorCh)
+	// Delivery is awaited for the API timeout in the producer, leave room for it
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		app.Config.GetAPICallTimeout()+time.Second,
+	)
+
+	go func() {
+		for {
+			select {
+			case result := <-resultCh:
+				if result.HasErrors() {
+					log.Error().Msg(result.String())
+					SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", result.String()))
+				} else {
+					SendStatus(result.String(), 5*time.Second, false)
+				}
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to produce messages")
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to produce messages: %s", err.Error()))
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while producing messages")
+				SendStatusWithDefaultTTL("[red]timeout while producing messages")
+				return
+			}
+		}
+	}()
+}
+
+func parseHeaders(text string) []kafka.Header {
+	var headers []kafka.Header
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		header := kafka.Header{Key: strings.TrimSpace(parts[0])}
+		if len(parts) == 2 {
+			header.Value = []byte(strings.TrimSpace(parts[1]))
+		}
+		if header.Key != "" {
+			headers = append(headers, header)
+		}
+	}
+	return headers
+}
@@ -27,6 +27,16 @@ const (
 	NodesResourceEventType EventType = "resources:nodes"
 	// SubjectsResourceEventType is the event type for subject resources.
 	SubjectsResourceEventType EventType = "resources:subjects"
+	// ACLsResourceEventType is the event type for ACL resources.
+	ACLsResourceEventType EventType = "resources:acls"
+	// UsersResourceEventType is the event type for SCRAM user resources.
+	UsersResourceEventType EventType = "resources:users"
+	// HealthResourceEventType is the event type for the cluster health resource.
+	HealthResourceEventType EventType = "resources:health"
+	// PolicyResourceEventType is the event type for the topic policy violations resource.
+	PolicyResourceEventType EventType = "resources:policy"
+	// AuditResourceEventType is the event type for the local audit log resource.
+	AuditResourceEventType EventType = "resources:audit"
 )
 
 var m = map[string]EventType{
@@ -36,6 +46,11 @@ var m = map[string]EventType{
 	Topics:           TopicsResourceEventType,
 	ConsumerGroups:   CgroupsResourceEventType,
 	Subjects:         SubjectsResourceEventType,
+	ACLs:             ACLsResourceEventType,
+	Users:            UsersResourceEventType,
+	Health:           HealthResourceEventType,
+	PolicyViolations: PolicyResourceEventType,
+	AuditLog:         AuditResourceEventType,
 }
 
 // ResourcesChannel is the channel for resource events.
@@ -77,6 +92,32 @@ func (app *App) RunResourcesEventHandler(ctx context.Context, in chan Event) {
 						continue
 					}
 					Publish(NodesChannel, GetNodesEventType, Payload{nil, false})
+				case "acl", ACLsResourceEventType:
+					if !app.isClusterSelected(app.Selected) {
+						SendStatusWithDefaultTTL("[red]to perform operation, select cluster")
+						continue
+					}
+					Publish(ACLsChannel, GetACLsEventType, Payload{nil, false})
+				case "usr", UsersResourceEventType:
+					if !app.isClusterSelected(app.Selected) {
+						SendStatusWithDefaultTTL("[red]to perform operation, select cluster")
+						continue
+					}
+					Publish(UsersChannel, GetUsersEventType, Payload{nil, false})
+				case "hlt", HealthResourceEventType:
+					if !app.isClusterSelected(app.Selected) {
+						SendStatusWithDefaultTTL("[red]to perform operation, select cluster")
+						continue
+					}
+					Publish(NodesChannel, GetHealthEventType, Payload{nil, false})
+				case "pol", PolicyResourceEventType:
+					if !app.isClusterSelected(app.Selected) {
+						SendStatusWithDefaultTTL("[red]to perform operation, select cluster")
+						continue
+					}
+					Publish(TopicsChannel, GetPolicyViolationsEventType, Payload{nil, false})
+				case "aud", AuditResourceEventType:
+					app.AuditLog()
 				case "sjs", SubjectsResourceEventType:
 					if !app.isSchemaRegistrySelected(app.Selected) {
 						SendStatusWithDefaultTTL(
@@ -109,6 +150,11 @@ func (app *App) NewResourcesPage() tview.Primitive {
 	table.SetCell(3, 0, tview.NewTableCell(Topics))
 	table.SetCell(4, 0, tview.NewTableCell(ConsumerGroups))
 	table.SetCell(5, 0, tview.NewTableCell(Subjects))
+	table.SetCell(6, 0, tview.NewTableCell(ACLs))
+	table.SetCell(7, 0, tview.NewTableCell(Users))
+	table.SetCell(8, 0, tview.NewTableCell(Health))
+	table.SetCell(9, 0, tview.NewTableCell(PolicyViolations))
+	table.SetCell(10, 0, tview.NewTableCell(AuditLog))
 
 	table.SetSelectedStyle(
 		tcell.StyleDefault.Foreground(
@@ -133,5 +179,6 @@ func (app *App) NewResourcesPage() tview.Primitive {
 		return event
 	})
 
-	return util.NewResourceModal(table)
+	// Rows plus the top and bottom border.
+	return util.NewResourceModal(table, table.GetRowCount()+2)
 }
@@ -0,0 +1,157 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+
+	"github.com/uraniumdawn/cinnamon/pkg/schemaregistry"
+)
+
+var protoKeywords = map[string]bool{
+	"syntax":     true,
+	"edition":    true,
+	"package":    true,
+	"import":     true,
+	"public":     true,
+	"weak":       true,
+	"option":     true,
+	"message":    true,
+	"enum":       true,
+	"service":    true,
+	"rpc":        true,
+	"returns":    true,
+	"stream":     true,
+	"repeated":   true,
+	"optional":   true,
+	"required":   true,
+	"oneof":      true,
+	"map":        true,
+	"reserved":   true,
+	"extend":     true,
+	"extensions": true,
+	"to":         true,
+	"max":        true,
+}
+
+var protoScalarTypes = map[string]bool{
+	"double":   true,
+	"float":    true,
+	"int32":    true,
+	"int64":    true,
+	"uint32":   true,
+	"uint64":   true,
+	"sint32":   true,
+	"sint64":   true,
+	"fixed32":  true,
+	"fixed64":  true,
+	"sfixed32": true,
+	"sfixed64": true,
+	"bool":     true,
+	"string":   true,
+	"bytes":    true,
+}
+
+// formatSchema renders a schema for display according to its type.
+// The returned text is already escaped and may contain color tags.
+func (app *App) formatSchema(schemaType, schema string) (string, error) {
+	switch schemaType {
+	case schemaregistry.SchemaTypeAvro, schemaregistry.SchemaTypeJSONSchema:
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, []byte(schema), "", "  "); err != nil {
+			return "", err
+		}
+		return tview.Escape(pretty.String()), nil
+	case schemaregistry.SchemaTypeProtobuf:
+		return app.highlightProto(schema), nil
+	default:
+		return "", fmt.Errorf("unsupported schema type '%s'", schemaType)
+	}
+}
+
+// highlightProto colors keywords, scalar types, strings and comments of a .proto source.
+func (app *App) highlightProto(src string) string {
+	keyword := app.Colors.Cinnamon.Title
+	scalar := app.Colors.Cinnamon.Label.FgColor
+	comment := app.Colors.Cinnamon.Placeholder
+
+	// Plain text is collected into runs and escaped at once, so that
+	// brackets of field options are not taken for color tags.
+	var b, plain strings.Builder
+	flush := func() {
+		b.WriteString(tview.Escape(plain.String()))
+		plain.Reset()
+	}
+	colored := func(color, text string) string {
+		flush()
+		return "[" + color + "]" + tview.Escape(text) + "[-]"
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			b.WriteString(colored(comment, src[i:i+end]))
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i
+			} else {
+				end += 4
+			}
+			b.WriteString(colored(comment, src[i:i+end]))
+			i += end
+		case c == '"' || c == '\'':
+			end := i + 1
+			for end < len(src) && src[end] != c && src[end] != '\n' {
+				if src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(src))
+			b.WriteString(colored("green", src[i:end]))
+			i = end
+		case isIdentStart(c):
+			end := i + 1
+			for end < len(src) && (isIdentStart(src[end]) || isDigit(src[end])) {
+				end++
+			}
+			word := src[i:end]
+			switch {
+			case protoKeywords[word]:
+				b.WriteString(colored(keyword, word))
+			case protoScalarTypes[word]:
+				b.WriteString(colored(scalar, word))
+			default:
+				plain.WriteString(word)
+			}
+			i = end
+		default:
+			plain.WriteByte(c)
+			i++
+		}
+	}
+	flush()
+	return b.String()
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -77,6 +77,17 @@ func (app *App) IsSearchInFocus() bool {
 	return false
 }
 
+// IsInputInFocus reports whether a text input has focus, so that
+// typed characters are not taken for global shortcuts.
+func (app *App) IsInputInFocus() bool {
+	switch app.GetFocus().(type) {
+	case *tview.InputField, *tview.TextArea:
+		return true
+	default:
+		return false
+	}
+}
+
 func (l *Layout) ShowInlineSearch(currentPage string) {
 	l.Content.Clear()
 	l.Content.AddItem(l.Header, headerHeight, 0, false)
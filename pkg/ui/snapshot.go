@@ -0,0 +1,82 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+)
+
+// ExportSnapshot shows the form asking for the file the snapshot of the selected
+// cluster is written to.
+func (app *App) ExportSnapshot() {
+	path := app.NewInputField("", 50).
+		SetText(client.SnapshotFileName(app.Selected.Cluster.Name, time.Now()))
+
+	app.showForm(
+		ExportSnapshot,
+		fmt.Sprintf(" Export Snapshot: %s ", app.Selected.Cluster.Name),
+		[]string{"File"},
+		[]tview.Primitive{path},
+		func() bool {
+			p := strings.TrimSpace(path.GetText())
+			if p == "" {
+				SendStatusWithDefaultTTL("[red]snapshot file is required")
+				return false
+			}
+			app.ExportSnapshotResultHandler(p)
+			return true
+		},
+	)
+}
+
+// ExportSnapshotResultHandler takes a snapshot of the selected cluster and writes it
+// to a file, as JSON when the file ends with .json and as YAML otherwise.
+func (app *App) ExportSnapshotResultHandler(path string) {
+	resultCh := make(chan *client.Snapshot)
+	errorCh := make(chan error)
+
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	SendStatusInfinite("taking cluster snapshot")
+	c.Snapshot(resultCh, errorCh)
+	// The snapshot runs a describe step per cluster, topics and consumer groups.
+	ctx, cancel := context.WithTimeout(context.Background(), 3*app.Config.GetAPICallTimeout())
+
+	go func() {
+		for {
+			select {
+			case snapshot := <-resultCh:
+				if err := snapshot.Write(path); err != nil {
+					log.Error().Err(err).Msg("failed to write cluster snapshot")
+					SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to write cluster snapshot: %s", err.Error()))
+				} else {
+					SendStatus(fmt.Sprintf("snapshot has been written to %s", path), 3*time.Second, false)
+				}
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to take cluster snapshot")
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to take cluster snapshot: %s", err.Error()))
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while taking cluster snapshot")
+				SendStatusWithDefaultTTL("[red]timeout while taking cluster snapshot")
+				return
+			}
+		}
+	}()
+}
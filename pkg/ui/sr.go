@@ -81,8 +81,7 @@ func (app *App) SchemaRegistriesTableInputHandler(st *tview.Table) {
 		sr := app.SchemaRegistries[name]
 
 		if event.Key() == tcell.KeyEnter {
-			app.SelectSchemaRegistry(sr, true)
-			ClearStatus()
+			app.SelectSchemaRegistry(sr, true, ClearStatus)
 		}
 
 		return event
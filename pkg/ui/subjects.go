@@ -5,12 +5,11 @@
 package ui
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/lithammer/fuzzysearch/fuzzy"
@@ -33,6 +32,13 @@ const (
 // SubjectsChannel is the channel for subject events.
 var SubjectsChannel = make(chan Event)
 
+const (
+	// subjectTypesBatch is the number of subject types buffered between refreshes.
+	subjectTypesBatch = 64
+	// subjectTypesInterval is how often looked up subject types are shown.
+	subjectTypesInterval = 500 * time.Millisecond
+)
+
 // SubjectVersionPair represents a subject and version pair.
 type SubjectVersionPair struct {
 	Subject string
@@ -99,8 +105,8 @@ func (app *App) RunSubjectsEventHandler(ctx context.Context, in chan Event) {
 
 // Subjects fetches and displays the list of schema subjects.
 func (app *App) Subjects() {
-	resultCh := make(chan []string)
-	errorCh := make(chan error)
+	resultCh := make(chan *schemaregistry.SubjectsResult, 1)
+	errorCh := make(chan error, 1)
 
 	c := app.GetCurrentSchemaRegistryClient()
 	SendStatusInfinite("getting subjects...")
@@ -114,7 +120,7 @@ func (app *App) Subjects() {
 				app.QueueUpdateDraw(func() {
 					table := app.NewSubjectsTable(subjects)
 					title := util.BuildTitle(Subjects,
-						"["+strconv.Itoa(len(subjects))+"]")
+						"["+strconv.Itoa(len(subjects.Subjects))+"]")
 					table.SetTitle(title)
 					table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 						if event.Key() == tcell.KeyCtrlU {
@@ -138,11 +144,9 @@ func (app *App) Subjects() {
 						return event
 					})
 
-					app.AddToPagesRegistry(
-						util.BuildPageKey(app.Selected.SchemaRegistry.Name, Subjects),
-						table,
-						SubjectsPageMenu, true,
-					)
+					pageName := util.BuildPageKey(app.Selected.SchemaRegistry.Name, Subjects)
+					app.AddToPagesRegistry(pageName, table, SubjectsPageMenu, true)
+					app.FillSubjectTypes(pageName, table, subjects)
 
 					app.AssignSearch(func(text string) {
 						filterSubjectsTable(table, subjects, text)
@@ -168,9 +172,69 @@ func (app *App) Subjects() {
 	}()
 }
 
+// FillSubjectTypes looks up the schema types of the subjects in the background and
+// fills the type column of their table as they arrive. Lookups stop when the page
+// is removed or replaced.
+func (app *App) FillSubjectTypes(
+	pageName string,
+	table *tview.Table,
+	subjects *schemaregistry.SubjectsResult,
+) {
+	typesCh := make(chan schemaregistry.SubjectType, subjectTypesBatch)
+	stop := make(chan struct{})
+	app.GetCurrentSchemaRegistryClient().SubjectTypes(subjects.Subjects, typesCh, stop)
+
+	go func() {
+		defer close(stop)
+		ticker := time.NewTicker(subjectTypesInterval)
+		defer ticker.Stop()
+
+		pending := make(map[string]string)
+		// flush shows the pending types and reports whether the page is still shown.
+		flush := func() bool {
+			if shown, _ := app.pageState(pageName, table); !shown {
+				log.Debug().Msg("stopped looking up subject types")
+				return false
+			}
+			if len(pending) == 0 {
+				return true
+			}
+			types := pending
+			pending = make(map[string]string)
+			app.QueueUpdateDraw(func() {
+				for subject, schemaType := range types {
+					subjects.Types[subject] = schemaType
+				}
+				for row := 0; row < table.GetRowCount(); row++ {
+					subject := table.GetCell(row, 0).Text
+					if schemaType, ok := types[subject]; ok {
+						populateSubjectsTable(table, row, subject, schemaType)
+					}
+				}
+			})
+			return true
+		}
+
+		for {
+			select {
+			case t, ok := <-typesCh:
+				if !ok {
+					flush()
+					return
+				}
+				pending[t.Subject] = t.Type
+			case <-ticker.C:
+				if !flush() {
+					return
+				}
+			}
+		}
+	}()
+}
+
 // Versions fetches and displays the versions for a specific subject.
 func (app *App) Versions(subject string) {
-	resultCh := make(chan []int)
+	resultCh := make(chan *schemaregistry.VersionsResult)
 	errorCh := make(chan error)
 
 	c := app.GetCurrentSchemaRegistryClient()
@@ -187,7 +251,8 @@ func (app *App) Versions(subject string) {
 					table.SetTitle(
 						util.BuildTitle(
 							subject,
-							"["+strconv.Itoa(len(versions))+"]",
+							versions.SchemaType,
+							"["+strconv.Itoa(len(versions.Versions))+"]",
 						),
 					)
 
@@ -254,20 +319,23 @@ func (app *App) Schema(subject string, version int) {
 		for {
 			select {
 			case result := <-resultCh:
-				var formattedSchema string
-				var pretty bytes.Buffer
-				indentErr := json.Indent(&pretty, []byte(result.Metadata.Schema), "", "  ")
-				if indentErr != nil {
-					errorCh <- indentErr
+				formattedSchema, formatErr := app.formatSchema(
+					schemaregistry.SchemaType(result.Metadata.SchemaInfo),
+					result.Metadata.Schema,
+				)
+				if formatErr != nil {
+					log.Error().Err(formatErr).Msg("failed to format schema")
+					SendStatusWithDefaultTTL(
+						fmt.Sprintf("[red]failed to format schema: %s", formatErr.Error()),
+					)
 					cancel()
 					return
 				}
-				formattedSchema = pretty.String()
 
 				app.QueueUpdateDraw(func() {
 					v := strconv.Itoa(version)
 					desc := app.NewDescription(
-						util.BuildTitle(subject, v),
+						util.BuildTitle(subject, v, schemaregistry.SchemaType(result.Metadata.SchemaInfo)),
 					)
 
 					desc.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
@@ -278,12 +346,7 @@ func (app *App) Schema(subject string, version int) {
 						return event
 					})
 
-					writer := tview.ANSIWriter(desc)
-					_, err := writer.Write([]byte(formattedSchema))
-					if err != nil {
-						log.Error().Err(err).Msg("failed to write formatted schema")
-						SendStatusWithDefaultTTL("[red]failed to write formatted schema")
-					}
+					desc.SetText(formattedSchema)
 					app.AddToPagesRegistry(
 						util.BuildPageKey(
 							app.Selected.SchemaRegistry.Name,
@@ -313,8 +376,8 @@ func (app *App) Schema(subject string, version int) {
 	}()
 }
 
-// NewSubjectsTable creates a table displaying schema subjects.
-func (app *App) NewSubjectsTable(subjects []string) *tview.Table {
+// NewSubjectsTable creates a table displaying schema subjects and their schema types.
+func (app *App) NewSubjectsTable(subjects *schemaregistry.SubjectsResult) *tview.Table {
 	table := tview.NewTable()
 	table.SetSelectable(true, false).
 		SetBorder(true).
@@ -329,15 +392,15 @@ func (app *App) NewSubjectsTable(subjects []string) *tview.Table {
 		)
 	}
 
-	for i, subject := range subjects {
-		table.SetCell(i, 0, tview.NewTableCell(subject))
+	for i, subject := range subjects.Subjects {
+		populateSubjectsTable(table, i, subject, subjects.Types[subject])
 	}
 
 	return table
 }
 
 // NewVersionsTable creates a table displaying schema versions.
-func (app *App) NewVersionsTable(versions []int) *tview.Table {
+func (app *App) NewVersionsTable(versions *schemaregistry.VersionsResult) *tview.Table {
 	table := tview.NewTable()
 	table.SetSelectable(true, false).
 		SetBorder(true).
@@ -354,24 +417,37 @@ func (app *App) NewVersionsTable(versions []int) *tview.Table {
 	}
 
 	row := 0
-	for _, version := range versions {
+	for _, version := range versions.Versions {
 		table.SetCell(row, 0, tview.NewTableCell(strconv.Itoa(version)))
+		table.SetCell(row, 1, tview.NewTableCell("TYPE: "+versions.SchemaType))
 		row++
 	}
 	return table
 }
 
-func filterSubjectsTable(table *tview.Table, subjects []string, filter string) {
+func filterSubjectsTable(
+	table *tview.Table,
+	subjects *schemaregistry.SubjectsResult,
+	filter string,
+) {
 	table.Clear()
 
-	ranks := fuzzy.RankFind(filter, subjects)
+	ranks := fuzzy.RankFind(filter, subjects.Subjects)
 	sort.Slice(ranks, func(i, j int) bool {
 		return ranks[i].Distance < ranks[j].Distance
 	})
 
 	row := 1
 	for _, rank := range ranks {
-		table.SetCell(row, 0, tview.NewTableCell(rank.Target))
+		populateSubjectsTable(table, row, rank.Target, subjects.Types[rank.Target])
 		row++
 	}
 }
+
+func populateSubjectsTable(table *tview.Table, row int, subject, schemaType string) {
+	if schemaType == "" {
+		schemaType = "-"
+	}
+	table.SetCell(row, 0, tview.NewTableCell(subject))
+	table.SetCell(row, 1, tview.NewTableCell("TYPE: "+schemaType))
+}
@@ -0,0 +1,66 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+)
+
+// SampleThroughput samples the end offsets of a topic on the monitor interval while
+// its description page is in front and refreshes the throughput section of the
+// description. Sampling stops when the page is removed or replaced.
+func (app *App) SampleThroughput(pageName string, desc *tview.TextView, result *client.TopicResult) {
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	name := result.Name
+	partitions := result.Partitions()
+	interval := app.Config.GetMonitorInterval()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var previous *client.OffsetsSample
+		for {
+			shown, front := app.pageState(pageName, desc)
+			if !shown {
+				log.Debug().Str("topic", name).Msg("stopped sampling topic throughput")
+				return
+			}
+
+			if front {
+				resultCh := make(chan *client.OffsetsSample, 1)
+				errorCh := make(chan error, 1)
+				c.SampleEndOffsets(name, partitions, resultCh, errorCh)
+
+				select {
+				case sample := <-resultCh:
+					if previous != nil {
+						result.SetThroughput(client.NewThroughput(previous, sample))
+						app.QueueUpdateDraw(func() {
+							desc.SetText(result.String())
+						})
+					}
+					previous = sample
+				case err := <-errorCh:
+					log.Error().Err(err).Str("topic", name).Msg("failed to sample end offsets")
+				case <-time.After(app.Config.GetAPICallTimeout()):
+					log.Error().Str("topic", name).Msg("timeout while sampling end offsets")
+				}
+			}
+
+			<-ticker.C
+		}
+	}()
+}
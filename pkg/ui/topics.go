@@ -19,6 +19,7 @@ import (
 	"github.com/rivo/tview"
 	"github.com/rs/zerolog/log"
 	"github.com/uraniumdawn/cinnamon/pkg/client"
+	"github.com/uraniumdawn/cinnamon/pkg/policy"
 	"github.com/uraniumdawn/cinnamon/pkg/util"
 )
 
@@ -41,6 +42,11 @@ func (app *App) RunTopicsEventHandler(ctx context.Context, in chan Event) {
 				log.Debug().Msg("shutting down topics event handler")
 				return
 			case event := <-in:
+				// Events of this handler read the selected cluster.
+				if !app.isClusterSelected(app.Selected) {
+					SendStatusWithDefaultTTL("[red]to perform operation, select cluster")
+					continue
+				}
 				switch event.Type {
 				case GetTopicsEventType:
 					pageName := util.BuildPageKey(app.Selected.Cluster.Name, Topics)
@@ -63,6 +69,16 @@ func (app *App) RunTopicsEventHandler(ctx context.Context, in chan Event) {
 						app.Topic(topicName)
 					}
 
+				case GetPolicyViolationsEventType:
+					pageName := util.BuildPageKey(app.Selected.Cluster.Name, PolicyViolations)
+					force := event.Payload.Force
+					_, found := app.Cache.Get(pageName)
+					if found && !force {
+						app.SwitchToPage(pageName)
+					} else {
+						app.PolicyViolations()
+					}
+
 				case CreateTopicEventType:
 					app.QueueUpdateDraw(func() {
 						app.CreateTopic()
@@ -104,7 +120,11 @@ func (app *App) Topics() {
 	resultCh := make(chan *client.TopicsResult)
 	errorCh := make(chan error)
 
-	c := app.GetCurrentKafkaClient()
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
 	SendStatusInfinite("getting topics")
 	c.Topics(resultCh, errorCh)
 	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
@@ -164,6 +184,44 @@ func (app *App) Topics() {
 							app.CliTemplates(topicName)
 						}
 
+						if event.Key() == tcell.KeyRune && event.Rune() == 'm' {
+							row, _ := table.GetSelection()
+							topicName := table.GetCell(row, 0).Text
+							app.ConsumeMessages(topicName)
+						}
+
+						if event.Key() == tcell.KeyRune && event.Rune() == 'P' {
+							row, _ := table.GetSelection()
+							topicName := table.GetCell(row, 0).Text
+							app.ProduceMessage(topicName)
+						}
+
+						if event.Key() == tcell.KeyRune && event.Rune() == 'v' {
+							row, _ := table.GetSelection()
+							topicName := table.GetCell(row, 0).Text
+							app.Peek(topicName)
+						}
+
+						if event.Key() == tcell.KeyRune && event.Rune() == 'T' {
+							row, _ := table.GetSelection()
+							topicName := table.GetCell(row, 0).Text
+							app.DeleteRecords(topicName)
+						}
+
+						if event.Key() == tcell.KeyRune && event.Rune() == 'L' {
+							row, _ := table.GetSelection()
+							topicName := table.GetCell(row, 0).Text
+							app.ElectLeaders(topicName)
+						}
+
+						if event.Key() == tcell.KeyRune && event.Rune() == 'A' {
+							app.PlanManifest()
+						}
+
+						if event.Key() == tcell.KeyRune && event.Rune() == 'V' {
+							Publish(TopicsChannel, GetPolicyViolationsEventType, Payload{nil, false})
+						}
+
 						return event
 					})
 
@@ -195,7 +253,11 @@ func (app *App) Topic(name string) {
 	resultCh := make(chan *client.TopicResult)
 	errorCh := make(chan error)
 
-	c := app.GetCurrentKafkaClient()
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
 	SendStatusInfinite("getting topic description")
 	c.DescribeTopic(name, resultCh, errorCh)
 	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
@@ -211,13 +273,19 @@ func (app *App) Topic(name string) {
 						if event.Key() == tcell.KeyCtrlU {
 							Publish(TopicsChannel, GetTopicEventType, Payload{name, true})
 						}
+
+						if event.Key() == tcell.KeyRune && event.Rune() == 'T' {
+							app.DeleteRecords(name)
+						}
+
+						if event.Key() == tcell.KeyRune && event.Rune() == 'L' {
+							app.ElectLeaders(name)
+						}
 						return event
 					})
-					app.AddToPagesRegistry(
-						util.BuildPageKey(app.Selected.Cluster.Name, Topic, name),
-						desc,
-						FinalPageMenu, false,
-					)
+					pageName := util.BuildPageKey(app.Selected.Cluster.Name, Topic, name)
+					app.AddToPagesRegistry(pageName, desc, TopicPageMenu, false)
+					app.SampleThroughput(pageName, desc, description)
 					ClearStatus()
 				})
 				cancel()
@@ -385,18 +453,42 @@ retention.ms=604800000`).
 			params.Partitions, _ = strconv.Atoi(partitions.GetText())
 			params.Config = parseConfig(configTextArea.GetText())
 
-			if err := params.validate(); err != nil {
+			rules, err := app.topicPolicies()
+			if err != nil {
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+				return event
+			}
+			c, err := app.GetCurrentKafkaClient()
+			if err != nil {
 				SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
 				return event
 			}
 
-			app.CreateTopicResultHandler(
-				params.TopicName,
-				params.ReplicationFactor,
-				params.Partitions,
-				params.Config,
-			)
-			app.HideModalPage(CreateTopic)
+			// Rules on entries left unset are checked with the defaults of the cluster.
+			SendStatusInfinite("checking topic policies")
+			go func() {
+				defaults, err := c.TopicDefaults(policy.ConfigKeys(rules))
+				app.QueueUpdateDraw(func() {
+					if err != nil {
+						log.Error().Err(err).Msg("failed to get topic defaults")
+						SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to get topic defaults: %s", err.Error()))
+						return
+					}
+					if err := params.validate(rules, defaults); err != nil {
+						SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+						return
+					}
+
+					ClearStatus()
+					app.CreateTopicResultHandler(
+						params.TopicName,
+						params.ReplicationFactor,
+						params.Partitions,
+						params.Config,
+					)
+					app.HideModalPage(CreateTopic)
+				})
+			}()
 		}
 
 		if event.Key() == tcell.KeyEsc {
@@ -426,7 +518,11 @@ func (app *App) CreateTopicResultHandler(
 	resultCh := make(chan bool)
 	errorCh := make(chan error)
 
-	c := app.GetCurrentKafkaClient()
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
 	SendStatusInfinite("creating topic")
 	c.CreateTopic(name, numPartitions, replicationFactor, config, resultCh, errorCh)
 	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
@@ -457,7 +553,11 @@ func (app *App) UpdateTopic(topicName string) {
 	resultCh := make(chan *client.TopicResult)
 	errorCh := make(chan error)
 
-	c := app.GetCurrentKafkaClient()
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
 	SendStatusInfinite("fetching topic configuration")
 	c.DescribeTopic(topicName, resultCh, errorCh)
 	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
@@ -496,7 +596,11 @@ func (app *App) UpdateTopicResultHandler(
 	resultCh := make(chan bool)
 	errorCh := make(chan error)
 
-	c := app.GetCurrentKafkaClient()
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
 	SendStatusInfinite("updating topic configuration")
 	c.UpdateTopicConfig(name, config, resultCh, errorCh)
 	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
@@ -528,40 +632,62 @@ func (app *App) UpdateTopicResultHandler(
 	}()
 }
 
-func (app *App) DeleteTopic(topicName string) {
-	messageText := tview.NewTextView().
-		SetText(fmt.Sprintf("Topic [red::b]%s[-::-] will be deleted. Confirm?", topicName)).
-		SetTextAlign(tview.AlignCenter).
-		SetDynamicColors(true)
-
-	messageText.SetBorder(true).
-		SetTitle(" Confirm Deletion ").
-		SetBorderPadding(0, 0, 1, 1)
+// CreatePartitionsResultHandler increases the partition count of a topic
+// and calls onSuccess once the partitions are created.
+func (app *App) CreatePartitionsResultHandler(name string, count int, onSuccess func()) {
+	resultCh := make(chan bool)
+	errorCh := make(chan error)
 
-	messageText.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		if event.Key() == tcell.KeyRune && event.Rune() == 's' {
-			app.DeleteTopicResultHandler(topicName)
-			app.HideModalPage(DeleteTopic)
-			Publish(TopicsChannel, GetTopicsEventType, Payload{nil, false})
-		}
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	SendStatusInfinite("creating partitions")
+	c.CreatePartitions(name, count, resultCh, errorCh)
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
 
-		if event.Key() == tcell.KeyEsc {
-			app.HideModalPage(DeleteTopic)
+	go func() {
+		for {
+			select {
+			case <-resultCh:
+				log.Info().Msgf("topic '%s' partitions increased to %d", name, count)
+				onSuccess()
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to create partitions")
+				SendStatusWithDefaultTTL(
+					fmt.Sprintf("[red]failed to create partitions: %s", err.Error()),
+				)
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while creating partitions")
+				SendStatusWithDefaultTTL("[red]timeout while creating partitions")
+				return
+			}
 		}
+	}()
+}
 
-		return event
+func (app *App) DeleteTopic(topicName string) {
+	message := fmt.Sprintf("Topic [red::b]%s[-::-] will be deleted. Confirm?", topicName)
+	app.confirmDestructive(DeleteTopic, " Confirm Deletion ", message, topicName, func() {
+		app.DeleteTopicResultHandler(topicName)
+		Publish(TopicsChannel, GetTopicsEventType, Payload{nil, false})
 	})
-
-	modal := util.NewConfirmationModal(messageText)
-	app.Layout.PagesRegistry.UI.Pages.AddPage(DeleteTopic, modal, true, true)
-	app.Layout.PagesRegistry.UI.Pages.ShowPage(DeleteTopic)
 }
 
 func (app *App) DeleteTopicResultHandler(name string) {
 	resultCh := make(chan bool)
 	errorCh := make(chan error)
 
-	c := app.GetCurrentKafkaClient()
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
 	SendStatusInfinite("deleting topic")
 	c.DeleteTopic(name, resultCh, errorCh)
 	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
@@ -628,6 +754,7 @@ func (app *App) NewUpdateTopicModal(topicName string, topicResult *client.TopicR
 	width := 40
 
 	currentConfig := make(map[string]string)
+	effectiveConfig := make(map[string]string)
 	partitionCount := 0
 	replicationFactor := 0
 
@@ -641,6 +768,7 @@ func (app *App) NewUpdateTopicModal(topicName string, topicResult *client.TopicR
 
 	for _, configResult := range topicResult.Config {
 		for _, entry := range configResult.Config {
+			effectiveConfig[entry.Name] = entry.Value
 			// Only include non-default, non-readonly configs
 			if !entry.IsDefault && !entry.IsReadOnly {
 				currentConfig[entry.Name] = entry.Value
@@ -660,11 +788,18 @@ func (app *App) NewUpdateTopicModal(topicName string, topicResult *client.TopicR
 		SetText(fmt.Sprintf("%d", replicationFactor))
 	replicationFactorField.SetDisabled(true)
 
-	partitionsField := tview.NewInputField().
-		SetFieldWidth(width).
-		SetFieldBackgroundColor(tcell.ColorDefault).
+	partitionsField := app.NewInputField("", width).
 		SetText(fmt.Sprintf("%d", partitionCount))
-	partitionsField.SetDisabled(true)
+	partitionsField.SetAcceptanceFunc(tview.InputFieldInteger)
+
+	warning := tview.NewTextView().SetDynamicColors(true).SetWrap(true)
+	partitionsField.SetChangedFunc(func(text string) {
+		warning.Clear()
+		if count, err := strconv.Atoi(text); err == nil && count > partitionCount {
+			warning.SetText("[yellow]Adding partitions changes the key-to-partition mapping, " +
+				"records with the same key may land in a different partition than before")
+		}
+	})
 
 	configTextArea := tview.NewTextArea()
 
@@ -683,6 +818,10 @@ func (app *App) NewUpdateTopicModal(topicName string, topicResult *client.TopicR
 	selection.SetCell(3, 0, tview.NewTableCell("Configs:").SetAlign(tview.AlignRight))
 	selection.SetSelectable(true, false)
 	selection.SetBorderPadding(0, 0, 1, 0)
+	partitionsField.SetDoneFunc(func(_ tcell.Key) {
+		app.SetFocus(selection)
+		app.Layout.Menu.SetMenu(EditTopicPageMenu)
+	})
 
 	f := tview.NewFlex()
 	f.SetDirection(tview.FlexColumn)
@@ -693,7 +832,8 @@ func (app *App) NewUpdateTopicModal(topicName string, topicResult *client.TopicR
 		AddItem(topicNameField, 1, 0, false).
 		AddItem(replicationFactorField, 1, 0, false).
 		AddItem(partitionsField, 1, 0, false).
-		AddItem(configTextArea, 0, 1, false)
+		AddItem(configTextArea, 0, 1, false).
+		AddItem(warning, 2, 0, false)
 
 	f.AddItem(inputs, 40, 0, false).
 		AddItem(tview.NewBox(), 0, 1, false)
@@ -715,6 +855,10 @@ func (app *App) NewUpdateTopicModal(topicName string, topicResult *client.TopicR
 		row, _ := selection.GetSelection()
 
 		if event.Key() == tcell.KeyEnter {
+			if row == 2 {
+				app.SetFocus(partitionsField)
+				app.Layout.Menu.SetMenu(EditTopicInputMenu)
+			}
 			if row == 3 {
 				app.SetFocus(configTextArea)
 				app.Layout.Menu.SetMenu(EditTopicInputMenu)
@@ -722,9 +866,40 @@ func (app *App) NewUpdateTopicModal(topicName string, topicResult *client.TopicR
 		}
 
 		if event.Key() == tcell.KeyRune && event.Rune() == 's' {
+			count, err := strconv.Atoi(partitionsField.GetText())
+			if err != nil || count < partitionCount {
+				SendStatusWithDefaultTTL(fmt.Sprintf(
+					"[red]partitions count must be at least the current count %d",
+					partitionCount,
+				))
+				return event
+			}
+
 			propertiesText := configTextArea.GetText()
 			editedConfig = parseConfig(propertiesText)
-			app.UpdateTopicResultHandler(topicName, editedConfig)
+
+			rules, err := app.topicPolicies()
+			if err != nil {
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+				return event
+			}
+			if err := checkTopicEdit(rules, policy.Topic{
+				Name:              topicName,
+				Partitions:        count,
+				ReplicationFactor: replicationFactor,
+				Config:            effectiveConfig,
+			}, partitionCount, editedConfig); err != nil {
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+				return event
+			}
+
+			if count > partitionCount {
+				app.CreatePartitionsResultHandler(topicName, count, func() {
+					app.UpdateTopicResultHandler(topicName, editedConfig)
+				})
+			} else {
+				app.UpdateTopicResultHandler(topicName, editedConfig)
+			}
 			app.HideModalPage(EditTopic)
 			Publish(TopicsChannel, GetTopicsEventType, Payload{nil, false})
 		}
@@ -798,7 +973,9 @@ func filterTopicsTable(
 	}
 }
 
-func (tp *TopicParams) validate() error {
+// validate checks the parameters and the policy rules of the cluster. Config entries
+// left unset are checked with their cluster default from defaults.
+func (tp *TopicParams) validate(rules []policy.Rule, defaults map[string]string) error {
 	if strings.TrimSpace(tp.TopicName) == "" {
 		return fmt.Errorf("topic name cannot be empty")
 	}
@@ -808,6 +985,16 @@ func (tp *TopicParams) validate() error {
 	if tp.Partitions <= 0 {
 		return fmt.Errorf("partitions must be greater than 0")
 	}
+
+	violations := policy.Check(rules, policy.Topic{
+		Name:              tp.TopicName,
+		Partitions:        tp.Partitions,
+		ReplicationFactor: tp.ReplicationFactor,
+		Config:            policy.WithDefaults(tp.Config, defaults),
+	})
+	if len(violations) > 0 {
+		return violationsError(violations)
+	}
 	return nil
 }
 
@@ -0,0 +1,180 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+	"github.com/uraniumdawn/cinnamon/pkg/util"
+)
+
+// DeleteRecords shows the form to choose the partitions and the offset records are deleted before.
+// Leaving both inputs empty purges the whole topic.
+func (app *App) DeleteRecords(topicName string) {
+	width := 40
+
+	partitions := app.NewInputField("Comma separated, empty for all", width)
+	offset := app.NewInputField("Empty for end offset", width)
+	offset.SetAcceptanceFunc(tview.InputFieldInteger)
+
+	selection := tview.NewTable()
+	selection.SetCell(0, 0, tview.NewTableCell("Partitions:").SetAlign(tview.AlignRight))
+	selection.SetCell(1, 0, tview.NewTableCell("Before offset:").SetAlign(tview.AlignRight))
+	selection.SetSelectable(true, false)
+	selection.SetBorderPadding(0, 0, 1, 0)
+	selection.SetSelectedStyle(
+		tcell.StyleDefault.Foreground(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.FgColor),
+		).Background(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.BgColor),
+		),
+	)
+
+	toSelection := func(key tcell.Key) {
+		app.SetFocus(selection)
+		app.Layout.Menu.SetMenu(DeleteRecordsPageMenu)
+	}
+	partitions.SetDoneFunc(toSelection)
+	offset.SetDoneFunc(toSelection)
+	inputs := []tview.Primitive{partitions, offset}
+
+	f := tview.NewFlex()
+	f.SetDirection(tview.FlexColumn)
+	f.AddItem(selection, 20, 0, true)
+	f.AddItem(tview.NewBox(), 3, 0, false)
+	f.AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(partitions, 1, 0, false).
+		AddItem(offset, 1, 0, false).
+		AddItem(tview.NewBox(), 0, 1, false), width, 0, false).
+		AddItem(tview.NewBox(), 0, 1, false)
+
+	selection.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := selection.GetSelection()
+
+		if event.Key() == tcell.KeyEnter && row < len(inputs) {
+			app.SetFocus(inputs[row])
+			app.Layout.Menu.SetMenu(DeleteRecordsInputMenu)
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 's' {
+			parsed, err := parsePartitions(partitions.GetText())
+			if err != nil {
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+				return event
+			}
+
+			before := kafka.OffsetEnd
+			if o := util.GetInt64(offset); o >= 0 {
+				before = kafka.Offset(o)
+			}
+			if len(parsed) == 0 && before != kafka.OffsetEnd {
+				SendStatusWithDefaultTTL("[red]an explicit offset requires partitions")
+				return event
+			}
+
+			offsets := make(map[int32]kafka.Offset)
+			for _, p := range parsed {
+				offsets[p] = before
+			}
+
+			app.HideModalPage(DeleteRecords)
+			app.ConfirmDeleteRecords(topicName, parsed, before, offsets)
+		}
+
+		if event.Key() == tcell.KeyEsc {
+			app.HideModalPage(DeleteRecords)
+		}
+
+		return event
+	})
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(f, 0, 1, true)
+	flex.SetTitle(fmt.Sprintf(" Delete Records: %s ", topicName))
+	flex.SetBorder(true)
+
+	modal := util.NewTopicModal(flex)
+	app.Layout.PagesRegistry.UI.Pages.AddPage(DeleteRecords, modal, true, false)
+	app.ShowModalPage(DeleteRecords)
+}
+
+// ConfirmDeleteRecords asks for confirmation before records are deleted.
+func (app *App) ConfirmDeleteRecords(
+	topicName string,
+	partitions []int32,
+	before kafka.Offset,
+	offsets map[int32]kafka.Offset,
+) {
+	scope := "all partitions"
+	if len(partitions) > 0 {
+		names := make([]string, len(partitions))
+		for i, p := range partitions {
+			names[i] = strconv.Itoa(int(p))
+		}
+		scope = "partitions " + strings.Join(names, ", ")
+	}
+	position := "the end offset"
+	if before != kafka.OffsetEnd {
+		position = "offset " + strconv.FormatInt(int64(before), 10)
+	}
+
+	message := fmt.Sprintf(
+		"Records of [red::b]%s[-::-] %s before %s will be deleted. Confirm?",
+		tview.Escape(topicName),
+		scope,
+		position,
+	)
+	app.confirmDestructive(ConfirmDeleteRecords, " Confirm Deletion ", message, topicName, func() {
+		app.DeleteRecordsResultHandler(topicName, offsets)
+	})
+}
+
+// DeleteRecordsResultHandler deletes records and refreshes the topic description
+// to show the new low watermarks.
+func (app *App) DeleteRecordsResultHandler(topicName string, offsets map[int32]kafka.Offset) {
+	resultCh := make(chan *client.DeleteRecordsResult)
+	errorCh := make(chan error)
+
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	SendStatusInfinite("deleting records")
+	c.DeleteRecords(topicName, offsets, resultCh, errorCh)
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
+
+	go func() {
+		for {
+			select {
+			case result := <-resultCh:
+				SendStatus(result.String(), 5*time.Second, false)
+				Publish(TopicsChannel, GetTopicEventType, Payload{topicName, true})
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to delete records")
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to delete records: %s", err.Error()))
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while deleting records")
+				SendStatusWithDefaultTTL("[red]timeout while deleting records")
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,330 @@
+// Copyright (c) Sergey Petrovsky
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/rs/zerolog/log"
+
+	"github.com/uraniumdawn/cinnamon/pkg/client"
+	"github.com/uraniumdawn/cinnamon/pkg/util"
+)
+
+const (
+	// GetUsersEventType is the event type for fetching SCRAM users.
+	GetUsersEventType EventType = "users:get"
+)
+
+// UsersChannel is the channel for SCRAM user events.
+var UsersChannel = make(chan Event)
+
+// RunUsersEventHandler processes SCRAM user events from the channel.
+func (app *App) RunUsersEventHandler(ctx context.Context, in chan Event) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				log.Debug().Msg("shutting down users event handler")
+				return
+			case event := <-in:
+				// Events of this handler read the selected cluster.
+				if !app.isClusterSelected(app.Selected) {
+					SendStatusWithDefaultTTL("[red]to perform operation, select cluster")
+					continue
+				}
+				switch event.Type {
+				case GetUsersEventType:
+					pageName := util.BuildPageKey(app.Selected.Cluster.Name, Users)
+					force := event.Payload.Force
+					_, found := app.Cache.Get(pageName)
+					if found && !force {
+						app.SwitchToPage(pageName)
+					} else {
+						app.Users()
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Users fetches and displays the SASL/SCRAM credentials of the cluster.
+func (app *App) Users() {
+	resultCh := make(chan []client.ScramCredential)
+	errorCh := make(chan error)
+
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	SendStatusInfinite("getting users")
+	c.ScramCredentials(resultCh, errorCh)
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
+
+	go func() {
+		for {
+			select {
+			case credentials := <-resultCh:
+				app.QueueUpdateDraw(func() {
+					table := app.NewUsersTable(credentials)
+					app.AddToPagesRegistry(
+						util.BuildPageKey(app.Selected.Cluster.Name, Users),
+						table,
+						UsersPageMenu, false,
+					)
+					ClearStatus()
+				})
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to list users")
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to list users: %s", err.Error()))
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while listing users")
+				SendStatusWithDefaultTTL("[red]timeout while listing users")
+				return
+			}
+		}
+	}()
+}
+
+// NewUsersTable creates a table displaying one row per user credential.
+func (app *App) NewUsersTable(credentials []client.ScramCredential) *tview.Table {
+	table := tview.NewTable()
+	table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetBorder(true).
+		SetBorderPadding(0, 0, 1, 0)
+	table.SetSelectedStyle(
+		tcell.StyleDefault.Foreground(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.FgColor),
+		).Background(
+			tcell.GetColor(app.Colors.Cinnamon.Selection.BgColor),
+		),
+	)
+	table.SetTitle(util.BuildTitle(Users, "["+strconv.Itoa(len(credentials))+"]"))
+
+	for i, h := range []string{"User", "Mechanism", "Iterations"} {
+		table.SetCell(0, i, tview.NewTableCell(h).
+			SetTextColor(tcell.GetColor(app.Colors.Cinnamon.Label.FgColor)).
+			SetSelectable(false))
+	}
+
+	for i, c := range credentials {
+		row := i + 1
+		table.SetCell(row, 0, tview.NewTableCell(c.User))
+		table.SetCell(row, 1, tview.NewTableCell(c.Mechanism.String()))
+		table.SetCell(row, 2, tview.NewTableCell(strconv.Itoa(c.Iterations)).SetExpansion(1))
+	}
+
+	selected := func() (client.ScramCredential, bool) {
+		row, _ := table.GetSelection()
+		if row < 1 || row > len(credentials) {
+			return client.ScramCredential{}, false
+		}
+		return credentials[row-1], true
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlU {
+			Publish(UsersChannel, GetUsersEventType, Payload{nil, true})
+		}
+
+		if event.Key() == tcell.KeyRune && event.Rune() == 'c' {
+			app.UpsertScramCredential(client.ScramCredential{})
+			return nil
+		}
+
+		if event.Key() == tcell.KeyEnter {
+			if c, ok := selected(); ok {
+				app.UpsertScramCredential(c)
+			}
+			return nil
+		}
+
+		if event.Key() == tcell.KeyCtrlD {
+			if c, ok := selected(); ok {
+				app.DeleteScramCredential(c)
+			}
+			return nil
+		}
+
+		return event
+	})
+
+	return table
+}
+
+// UpsertScramCredential shows the form to create a credential or rotate the password
+// of an existing one. Password inputs are masked.
+func (app *App) UpsertScramCredential(current client.ScramCredential) {
+	width := 40
+
+	user := app.NewInputField("Username", width).SetText(current.User)
+	mechanism := app.newEnumDropDown(width, len(client.ScramMechanisms), func(i int) string {
+		return client.ScramMechanisms[i].String()
+	})
+	for i, m := range client.ScramMechanisms {
+		if m == current.Mechanism {
+			mechanism.SetCurrentOption(i)
+		}
+	}
+	iterations := app.NewInputField(strconv.Itoa(client.DefaultScramIterations), width)
+	iterations.SetAcceptanceFunc(tview.InputFieldInteger)
+	if current.Iterations > 0 {
+		iterations.SetText(strconv.Itoa(current.Iterations))
+	}
+	password := app.NewInputField("", width).SetMaskCharacter('*')
+	confirmation := app.NewInputField("", width).SetMaskCharacter('*')
+
+	title := " Create Credential "
+	if current.User != "" {
+		title = fmt.Sprintf(" Update Credential: %s ", current.User)
+	}
+
+	app.showForm(
+		UpsertCredential,
+		title,
+		[]string{"User:", "Mechanism:", "Iterations:", "Password:", "Confirm password:"},
+		[]tview.Primitive{user, mechanism, iterations, password, confirmation},
+		func() bool {
+			name := strings.TrimSpace(user.GetText())
+			if name == "" {
+				SendStatusWithDefaultTTL("[red]user must not be empty")
+				return false
+			}
+			if password.GetText() == "" {
+				SendStatusWithDefaultTTL("[red]password must not be empty")
+				return false
+			}
+			if password.GetText() != confirmation.GetText() {
+				SendStatusWithDefaultTTL("[red]passwords do not match")
+				return false
+			}
+
+			count := client.DefaultScramIterations
+			if n := util.GetInt64(iterations); n > 0 {
+				count = int(n)
+			}
+			if count < client.DefaultScramIterations {
+				SendStatusWithDefaultTTL(fmt.Sprintf(
+					"[red]iterations must be at least %d",
+					client.DefaultScramIterations,
+				))
+				return false
+			}
+
+			m, _ := mechanism.GetCurrentOption()
+			credential := client.ScramCredential{User: name}
+			credential.Mechanism = client.ScramMechanisms[m]
+			credential.Iterations = count
+
+			app.UpsertScramCredentialResultHandler(credential, []byte(password.GetText()))
+			return true
+		},
+	)
+}
+
+// UpsertScramCredentialResultHandler creates or updates a credential and refreshes the users page.
+func (app *App) UpsertScramCredentialResultHandler(credential client.ScramCredential, password []byte) {
+	resultCh := make(chan bool)
+	errorCh := make(chan error)
+
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	SendStatusInfinite("saving credential")
+	c.UpsertScramCredential(credential, password, resultCh, errorCh)
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
+
+	go func() {
+		for {
+			select {
+			case <-resultCh:
+				SendStatus(
+					fmt.Sprintf(
+						"%s credential of user '%s' has been saved",
+						credential.Mechanism,
+						tview.Escape(credential.User),
+					),
+					2*time.Second,
+					false,
+				)
+				Publish(UsersChannel, GetUsersEventType, Payload{nil, true})
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to save credential")
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to save credential: %s", err.Error()))
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while saving credential")
+				SendStatusWithDefaultTTL("[red]timeout while saving credential")
+				return
+			}
+		}
+	}()
+}
+
+// DeleteScramCredential asks for confirmation before deleting a credential.
+func (app *App) DeleteScramCredential(credential client.ScramCredential) {
+	message := fmt.Sprintf(
+		"%s credential of user [red::b]%s[-::-] will be deleted. Confirm?",
+		credential.Mechanism,
+		tview.Escape(credential.User),
+	)
+	app.confirmDestructive(DeleteCredential, " Confirm Deletion ", message, credential.User, func() {
+		app.DeleteScramCredentialResultHandler(credential)
+	})
+}
+
+// DeleteScramCredentialResultHandler deletes a credential and refreshes the users page.
+func (app *App) DeleteScramCredentialResultHandler(credential client.ScramCredential) {
+	resultCh := make(chan bool)
+	errorCh := make(chan error)
+
+	c, err := app.GetCurrentKafkaClient()
+	if err != nil {
+		SendStatusWithDefaultTTL(fmt.Sprintf("[red]%s", err.Error()))
+		return
+	}
+	SendStatusInfinite("deleting credential")
+	c.DeleteScramCredential(credential, resultCh, errorCh)
+	ctx, cancel := context.WithTimeout(context.Background(), app.Config.GetAPICallTimeout())
+
+	go func() {
+		for {
+			select {
+			case <-resultCh:
+				SendStatus("credential has been deleted", 2*time.Second, false)
+				Publish(UsersChannel, GetUsersEventType, Payload{nil, true})
+				cancel()
+				return
+			case err := <-errorCh:
+				log.Error().Err(err).Msg("failed to delete credential")
+				SendStatusWithDefaultTTL(fmt.Sprintf("[red]failed to delete credential: %s", err.Error()))
+				cancel()
+				return
+			case <-ctx.Done():
+				log.Error().Msg("timeout while deleting credential")
+				SendStatusWithDefaultTTL("[red]timeout while deleting credential")
+				return
+			}
+		}
+	}()
+}
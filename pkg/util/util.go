@@ -152,12 +152,12 @@ func NewConfirmationModal(p tview.Primitive) tview.Primitive {
 		AddItem(nil, 1, 0, false)
 }
 
-func NewResourceModal(p tview.Primitive) tview.Primitive {
+func NewResourceModal(p tview.Primitive, height int) tview.Primitive {
 	return tview.NewFlex().
 		AddItem(nil, 0, 1, false).
 		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
 			AddItem(nil, 0, 1, false).
-			AddItem(p, 8, 0, true).
+			AddItem(p, height, 0, true).
 			AddItem(nil, 0, 9, false), 0, 2, true).
 		AddItem(nil, 0, 1, false)
 }
@@ -250,3 +250,32 @@ func SetSearchableTableTitle(table *tview.Table, title, filter string) {
 		table.SetTitle(title)
 	}
 }
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders the last width values as a bar per value scaled between
+// the minimum and maximum of the rendered values.
+func Sparkline(values []float64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if len(values) == 0 {
+		return ""
+	}
+
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+
+	var builder strings.Builder
+	for _, v := range values {
+		i := 0
+		if hi > lo {
+			i = int((v - lo) / (hi - lo) * float64(len(sparks)-1))
+		}
+		builder.WriteRune(sparks[i])
+	}
+	return builder.String()
+}